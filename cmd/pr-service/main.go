@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"pr-service/internal/config"
+	exclusionhandlers "pr-service/internal/http-server/handlers/exclusions"
 	prhandlers "pr-service/internal/http-server/handlers/pullrequest"
 	statshandlers "pr-service/internal/http-server/handlers/stats"
 	teamhandlers "pr-service/internal/http-server/handlers/team"
 	userhandlers "pr-service/internal/http-server/handlers/users"
 	"pr-service/internal/lib/logger/handlers/slogpretty"
 	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/notifier"
+	"pr-service/internal/scheduler"
+	"pr-service/internal/selector"
 	"pr-service/internal/storage/sqlite"
 
 	mwLogger "pr-service/internal/http-server/middleware/logger"
@@ -34,13 +42,85 @@ func main() {
 	// Инициализируем логгер
 	log := setupLogger(cfg.Env)
 
+	// Инициализируем стратегии выбора ревьюверов
+	selectors, err := selector.NewRegistry(cfg.Assignment.Strategy, cfg.Assignment.TeamStrategies, cfg.Assignment.StickyHalfLife)
+	if err != nil {
+		log.Error("failed to init reviewer selectors", sl.Err(err))
+		os.Exit(1)
+	}
+
+	// Источник случайности: с заданным сидом назначения воспроизводятся
+	source := selector.NewSource(cfg.Assignment.Seed)
+	log.Info("reviewer selection seeded", slog.Int64("seed", source.Seed()))
+
 	// Инициализируем хранилище
-	storage, err := sqlite.New(cfg.StoragePath)
+	storage, err := sqlite.New(cfg.StoragePath, selectors, source)
 	if err != nil {
 		log.Error("failed to init storage", sl.Err(err))
 		os.Exit(1)
 	}
 
+	// Фоновые задачи
+	sched := scheduler.New(log, cfg.Scheduler.Interval)
+	sched.Add("absences", func(now time.Time) error {
+		res, err := storage.ProcessAbsences(now)
+		if err != nil {
+			return err
+		}
+		if len(res.StartedUserIDs) > 0 || len(res.EndedUserIDs) > 0 {
+			log.Info("absences processed",
+				slog.Any("started", res.StartedUserIDs),
+				slog.Any("ended", res.EndedUserIDs),
+				slog.Int("reassigned", res.ReassignedCount),
+				slog.Int("removed", res.RemovedAssignments),
+				slog.Any("senior_unavailable", res.SeniorUnavailable),
+			)
+		}
+		return nil
+	})
+	sched.Add("review_sla", func(now time.Time) error {
+		res, err := storage.ProcessReviewSLA(now)
+		if err != nil {
+			return err
+		}
+		for _, b := range res.Breaches {
+			log.Info("review sla breached",
+				slog.String("pull_request_id", b.PullRequestID),
+				slog.String("user_id", b.UserID),
+				slog.String("outcome", b.Outcome),
+				slog.String("replaced_by", b.ReplacedBy),
+			)
+		}
+		return nil
+	})
+	if cfg.Reminders.Threshold > 0 {
+		notify, err := notifier.New(cfg.Reminders.Notifier, log, cfg.Reminders.WebhookURL, cfg.Reminders.WebhookTimeout)
+		if err != nil {
+			log.Error("failed to init reminder notifier", sl.Err(err))
+			os.Exit(1)
+		}
+		sched.Add("reminders", func(now time.Time) error {
+			due, err := storage.DueReminders(now, cfg.Reminders.Threshold, cfg.Reminders.MinInterval)
+			if err != nil {
+				return err
+			}
+			// недоставленное напоминание не отмечается и уйдёт в следующий проход
+			var errs []error
+			for _, r := range due {
+				if err := notify.Remind(r); err != nil {
+					errs = append(errs, fmt.Errorf("remind %s about %s: %w", r.UserID, r.PullRequestID, err))
+					continue
+				}
+				// не отметили — напомним повторно, но остальным напоминания всё равно уходят
+				if err := storage.MarkReminderSent(r.PullRequestID, r.UserID, now); err != nil {
+					errs = append(errs, fmt.Errorf("mark reminder %s about %s: %w", r.UserID, r.PullRequestID, err))
+				}
+			}
+			return errors.Join(errs...)
+		})
+	}
+	go sched.Run(context.Background())
+
 	// Инициализируем роутер
 	router := chi.NewRouter()
 
@@ -57,18 +137,42 @@ func main() {
 	router.Post("/team/add", teamhandlers.Add(log, storage))
 	router.Get("/team/get", teamhandlers.Get(log, storage))
 	router.Post("/team/deactivateUsers", teamhandlers.DeactivateUsers(log, storage))
+	router.Post("/team/rebalance", teamhandlers.Rebalance(log, storage, cfg.Assignment.RebalanceTolerance))
+	router.Get("/team/settings", teamhandlers.GetSettings(log, storage))
+	router.Post("/team/settings", teamhandlers.UpdateSettings(log, storage))
+	router.Get("/team/codeowners", teamhandlers.GetCodeOwners(log, storage))
+	router.Post("/team/codeowners", teamhandlers.SetCodeOwners(log, storage))
 
 	// Users
 	router.Post("/users/setIsActive", userhandlers.SetIsActive(log, storage))
+	router.Post("/users/setMaxOpenReviews", userhandlers.SetMaxOpenReviews(log, storage))
+	router.Post("/users/update", userhandlers.Update(log, storage))
+	router.Post("/users/setAbsence", userhandlers.SetAbsence(log, storage))
 	router.Get("/users/getReview", userhandlers.GetReview(log, storage))
 
+	// Reviewer exclusions
+	router.Post("/exclusions/add", exclusionhandlers.Add(log, storage))
+	router.Post("/exclusions/remove", exclusionhandlers.Remove(log, storage))
+	router.Get("/exclusions/list", exclusionhandlers.List(log, storage))
+
 	// PullRequests
 	router.Post("/pullRequest/create", prhandlers.Create(log, storage))
+	router.Post("/pullRequest/previewAssignment", prhandlers.Preview(log, storage))
+	router.Get("/pullRequest/get", prhandlers.Get(log, storage))
+	router.Get("/pullRequest/list", prhandlers.List(log, storage))
+	router.Get("/pullRequest/history", prhandlers.History(log, storage))
 	router.Post("/pullRequest/merge", prhandlers.Merge(log, storage))
+	router.Post("/pullRequest/ready", prhandlers.Ready(log, storage))
+	router.Post("/pullRequest/close", prhandlers.Close(log, storage))
+	router.Post("/pullRequest/reopen", prhandlers.Reopen(log, storage))
 	router.Post("/pullRequest/reassign", prhandlers.Reassign(log, storage))
+	router.Post("/pullRequest/decline", prhandlers.Decline(log, storage))
+	router.Post("/pullRequest/review", prhandlers.Review(log, storage))
+	router.Get("/pullRequest/pending", prhandlers.Pending(log, storage))
 
 	// Stats
 	router.Get("/stats", statshandlers.Get(log, storage))
+	router.Get("/stats/sla", statshandlers.SLA(log, storage))
 
 	// Запускаем сервис
 
@@ -13,6 +13,9 @@ type Config struct {
 	Env         string `yaml:"env"  env-default:"local"`
 	StoragePath string `yaml:"storage_path"  env-required:"true"`
 	HTTPServer  `yaml:"http_server"`
+	Assignment  Assignment `yaml:"assignment"`
+	Scheduler   Scheduler  `yaml:"scheduler"`
+	Reminders   Reminders  `yaml:"reminders"`
 }
 
 type HTTPServer struct {
@@ -23,6 +26,31 @@ type HTTPServer struct {
 	Password    string        `yaml:"password" env-required:"true" env:"HTTP_SERVER_PASSWORD"`
 }
 
+// Assignment — настройки автоназначения ревьюверов
+type Assignment struct {
+	Strategy       string            `yaml:"strategy" env-default:"random"` // random, round_robin, least_loaded, sticky
+	TeamStrategies map[string]string `yaml:"team_strategies"`               // переопределение стратегии для отдельных команд
+	Seed           int64             `yaml:"seed" env:"ASSIGNMENT_SEED"`    // сид случайного выбора; 0 — случайный при старте
+	// допустимая разница открытых ревью в команде для /team/rebalance
+	RebalanceTolerance int `yaml:"rebalance_tolerance" env-default:"1"`
+	// за сколько вдвое затухает вклад прошлого ревью в стратегии sticky
+	StickyHalfLife time.Duration `yaml:"sticky_half_life" env-default:"336h"`
+}
+
+// Scheduler — фоновые задачи (начало и конец отсутствий пользователей, SLA ревью, напоминания)
+type Scheduler struct {
+	Interval time.Duration `yaml:"interval" env-default:"1m"` // как часто запускать проход
+}
+
+// Reminders — напоминания ревьюверам о PR, которые долго ждут итога ревью
+type Reminders struct {
+	Threshold      time.Duration `yaml:"threshold" env-default:"24h"`   // сколько назначение висит без итога до первого напоминания; 0 — выключено
+	MinInterval    time.Duration `yaml:"min_interval" env-default:"4h"` // не чаще раза в этот срок по одному PR одному ревьюверу
+	Notifier       string        `yaml:"notifier" env-default:"log"`    // log, webhook
+	WebhookURL     string        `yaml:"webhook_url" env:"REMINDERS_WEBHOOK_URL"`
+	WebhookTimeout time.Duration `yaml:"webhook_timeout" env-default:"5s"`
+}
+
 func MustLoad() *Config {
 	godotenv.Load()
 
@@ -41,5 +69,10 @@ func MustLoad() *Config {
 		log.Fatalf("cannot read config: %s", err)
 	}
 
+	// time.NewTicker паникует на неположительном интервале
+	if cfg.Scheduler.Interval <= 0 {
+		log.Fatalf("scheduler.interval must be positive, got %s", cfg.Scheduler.Interval)
+	}
+
 	return &cfg
 }
@@ -0,0 +1,249 @@
+package exclusions
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type PairRequest struct {
+	UserID      string `json:"user_id"`
+	OtherUserID string `json:"other_user_id"`
+	Reason      string `json:"reason,omitempty"` // только для add
+}
+
+type ExclusionResponse struct {
+	UserID      string     `json:"user_id"`
+	OtherUserID string     `json:"other_user_id"`
+	Reason      string     `json:"reason"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+}
+
+type AddResponse struct {
+	Exclusion ExclusionResponse `json:"exclusion"`
+}
+
+type ListResponse struct {
+	Exclusions []ExclusionResponse `json:"exclusions"`
+}
+
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Handlers
+
+// POST /exclusions/add
+func Add(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.exclusions.add"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		req, ok := decodePair(w, r, log)
+		if !ok {
+			return
+		}
+
+		ex, err := repo.AddReviewerExclusion(req.UserID, req.OtherUserID, req.Reason)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrNotFound):
+				log.Info("user not found",
+					slog.String("user_id", req.UserID),
+					slog.String("other_user_id", req.OtherUserID),
+				)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrInvalidExclusion):
+				log.Info("invalid exclusion", sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_EXCLUSION",
+						Message: err.Error(),
+					},
+				})
+
+				return
+
+			default:
+				log.Error("failed to add exclusion", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("internal error"))
+
+				return
+			}
+		}
+
+		log.Info("exclusion added",
+			slog.String("user_id", ex.UserID),
+			slog.String("other_user_id", ex.OtherUserID),
+		)
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, AddResponse{Exclusion: mapExclusionToResponse(ex)})
+	}
+}
+
+// POST /exclusions/remove
+func Remove(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.exclusions.remove"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		req, ok := decodePair(w, r, log)
+		if !ok {
+			return
+		}
+
+		if err := repo.RemoveReviewerExclusion(req.UserID, req.OtherUserID); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("exclusion not found",
+					slog.String("user_id", req.UserID),
+					slog.String("other_user_id", req.OtherUserID),
+				)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to remove exclusion", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		log.Info("exclusion removed",
+			slog.String("user_id", req.UserID),
+			slog.String("other_user_id", req.OtherUserID),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+	}
+}
+
+// GET /exclusions/list?user_id=...  (без user_id — все пары)
+func List(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.exclusions.list"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID := r.URL.Query().Get("user_id")
+
+		exclusions, err := repo.ListReviewerExclusions(userID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("user not found", slog.String("user_id", userID))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to list exclusions", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		res := ListResponse{Exclusions: make([]ExclusionResponse, 0, len(exclusions))}
+		for _, ex := range exclusions {
+			res.Exclusions = append(res.Exclusions, mapExclusionToResponse(ex))
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
+
+func decodePair(w http.ResponseWriter, r *http.Request, log *slog.Logger) (PairRequest, bool) {
+	var req PairRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid request body"))
+
+		return PairRequest{}, false
+	}
+
+	if req.UserID == "" || req.OtherUserID == "" {
+		log.Warn("missing required fields",
+			slog.String("user_id", req.UserID),
+			slog.String("other_user_id", req.OtherUserID),
+		)
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("user_id and other_user_id are required"))
+
+		return PairRequest{}, false
+	}
+
+	return req, true
+}
+
+func mapExclusionToResponse(ex storage.ReviewerExclusion) ExclusionResponse {
+	return ExclusionResponse{
+		UserID:      ex.UserID,
+		OtherUserID: ex.OtherUserID,
+		Reason:      ex.Reason,
+		CreatedAt:   ex.CreatedAt,
+	}
+}
@@ -3,6 +3,7 @@ package pullrequest
 import (
 	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"log/slog"
@@ -18,9 +19,15 @@ import (
 // DTO
 
 type CreateRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID     string   `json:"pull_request_id"`
+	PullRequestName   string   `json:"pull_request_name"`
+	AuthorID          string   `json:"author_id"`
+	ChangedFiles      []string `json:"changed_files,omitempty"`      // владельцы этих путей из CODEOWNERS назначаются в первую очередь
+	Labels            []string `json:"labels,omitempty"`             // предпочитаются ревьюверы с такими навыками
+	ExcludedReviewers []string `json:"excluded_reviewers,omitempty"` // кого нельзя назначать на этот PR (помимо пар-исключений автора)
+	// кого назначить явно; остальные места до reviewer_count добираются автоматически
+	RequestedReviewers []string `json:"requested_reviewers,omitempty"`
+	Draft              bool     `json:"draft,omitempty"` // черновик: ревьюверы назначаются в /pullRequest/ready
 }
 
 type CreateResponse struct {
@@ -28,13 +35,51 @@ type CreateResponse struct {
 }
 
 type PRResponse struct {
-	PullRequestID     string     `json:"pull_request_id"`
-	PullRequestName   string     `json:"pull_request_name"`
-	AuthorID          string     `json:"author_id"`
-	Status            string     `json:"status"`
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         *time.Time `json:"createdAt,omitempty"`
-	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	PullRequestID             string                  `json:"pull_request_id"`
+	PullRequestName           string                  `json:"pull_request_name"`
+	AuthorID                  string                  `json:"author_id"`
+	Status                    string                  `json:"status"` // DRAFT, OPEN, MERGED, CLOSED
+	Labels                    []string                `json:"labels"`
+	ExcludedReviewers         []string                `json:"excluded_reviewers,omitempty"`
+	RequestedReviewers        []string                `json:"requested_reviewers,omitempty"`         // черновик: кого назначить явно в /pullRequest/ready
+	DroppedRequestedReviewers []InvalidReviewer       `json:"dropped_requested_reviewers,omitempty"` // ready: явно указанные, ставшие недоступными
+	AssignedReviewers         []string                `json:"assigned_reviewers"`
+	Reviewers                 []ReviewerResponse      `json:"reviewers"`
+	Declines                  []ReviewDeclineResponse `json:"declines,omitempty"` // кто отказался от ревью
+	PendingReviewers          int                     `json:"pending_reviewers,omitempty"`
+	PolicyWarnings            []string                `json:"policy_warnings,omitempty"` // политики команды, которые не удалось выполнить
+	CreatedAt                 *time.Time              `json:"createdAt,omitempty"`
+	MergedAt                  *time.Time              `json:"mergedAt,omitempty"`
+	ClosedAt                  *time.Time              `json:"closedAt,omitempty"`
+}
+
+// ReviewerResponse — подробности назначения; assigned_reviewers оставлен для совместимости
+type ReviewerResponse struct {
+	UserID        string                  `json:"user_id"`
+	Source        string                  `json:"source"` // team, codeowner, fallback, requested
+	TeamName      string                  `json:"team_name,omitempty"`
+	MatchedLabels []string                `json:"matched_labels,omitempty"` // метки PR, совпавшие с навыками ревьювера
+	Strategy      string                  `json:"strategy,omitempty"`       // стратегия, которой выбран ревьювер
+	SelectionSeed string                  `json:"selection_seed,omitempty"` // сид выбора (строкой, чтобы не терять точность в JSON)
+	Verdict       string                  `json:"verdict,omitempty"`        // APPROVED, CHANGES_REQUESTED, COMMENTED; последний
+	VerdictAt     *time.Time              `json:"verdictAt,omitempty"`
+	Timing        *ReviewerTimingResponse `json:"timing,omitempty"`
+}
+
+// ReviewerTimingResponse — хронология назначения; длительности в секундах от assignedAt
+type ReviewerTimingResponse struct {
+	AssignedAt               *time.Time `json:"assignedAt,omitempty"`    // от этого момента считается SLA ревью
+	FirstActionAt            *time.Time `json:"firstActionAt,omitempty"` // первое ревью любого типа
+	CompletedAt              *time.Time `json:"completedAt,omitempty"`   // первый APPROVED или CHANGES_REQUESTED
+	TimeToFirstActionSeconds *int64     `json:"time_to_first_action_seconds,omitempty"`
+	TimeToCompleteSeconds    *int64     `json:"time_to_complete_seconds,omitempty"`
+}
+
+type ReviewDeclineResponse struct {
+	UserID     string    `json:"user_id"`
+	Reason     string    `json:"reason"` // CONFLICT, NO_EXPERTISE, OVERLOADED
+	ReplacedBy string    `json:"replaced_by,omitempty"`
+	DeclinedAt time.Time `json:"declinedAt"`
 }
 
 type ErrorResponse struct {
@@ -46,6 +91,22 @@ type ErrorBody struct {
 	Message string `json:"message"`
 }
 
+// InvalidReviewersResponse — ошибка с причиной для каждого неподходящего requested_reviewers
+type InvalidReviewersResponse struct {
+	Error InvalidReviewersBody `json:"error"`
+}
+
+type InvalidReviewersBody struct {
+	Code      string            `json:"code"`
+	Message   string            `json:"message"`
+	Reviewers []InvalidReviewer `json:"reviewers"`
+}
+
+type InvalidReviewer struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"` // NOT_FOUND, AUTHOR, NOT_TEAM_MEMBER, EXCLUDED_FOR_PR, EXCLUSION_PAIR, INACTIVE, ABSENT
+}
+
 // Handler
 
 // POST /pullRequest/create
@@ -81,8 +142,19 @@ func Create(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 			return
 		}
 
-		pr, err := repo.CreatePullRequestWithAutoAssign(req.PullRequestID, req.PullRequestName, req.AuthorID)
+		pr, err := repo.CreatePullRequestWithAutoAssign(storage.NewPullRequest{
+			ID:                 req.PullRequestID,
+			Name:               req.PullRequestName,
+			AuthorID:           req.AuthorID,
+			ChangedFiles:       req.ChangedFiles,
+			Labels:             req.Labels,
+			ExcludedReviewers:  req.ExcludedReviewers,
+			RequestedReviewers: req.RequestedReviewers,
+			Draft:              req.Draft,
+		})
 		if err != nil {
+			var invalid *storage.InvalidReviewersError
+
 			switch {
 			case errors.Is(err, storage.ErrPRExists):
 				log.Info("pull request already exists", slog.String("pull_request_id", req.PullRequestID))
@@ -97,6 +169,17 @@ func Create(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 
 				return
 
+			case errors.As(err, &invalid):
+				log.Info("invalid requested reviewers",
+					slog.String("pull_request_id", req.PullRequestID),
+					slog.Int("invalid", len(invalid.Reviewers)),
+				)
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, mapInvalidReviewersToResponse(invalid))
+
+				return
+
 			case errors.Is(err, storage.ErrNotFound):
 				// Автор или его команда не найдены
 				log.Info("author or team not found when creating PR",
@@ -132,6 +215,8 @@ func Create(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 			slog.String("pull_request_id", pr.ID),
 			slog.String("author_id", pr.AuthorID),
 			slog.String("status", pr.Status),
+			slog.Int("pending_reviewers", pr.PendingReviewers),
+			slog.Any("policy_warnings", pr.Warnings),
 		)
 
 		render.Status(r, http.StatusCreated)
@@ -140,13 +225,88 @@ func Create(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 }
 
 func mapPullRequestToResponse(pr storage.PullRequest) PRResponse {
-	return PRResponse{
-		PullRequestID:     pr.ID,
-		PullRequestName:   pr.Name,
-		AuthorID:          pr.AuthorID,
-		Status:            pr.Status,
-		AssignedReviewers: pr.AssignedReviewers,
-		CreatedAt:         pr.CreatedAt,
-		MergedAt:          pr.MergedAt,
+	res := PRResponse{
+		PullRequestID:      pr.ID,
+		PullRequestName:    pr.Name,
+		AuthorID:           pr.AuthorID,
+		Status:             pr.Status,
+		Labels:             pr.Labels,
+		ExcludedReviewers:  pr.ExcludedReviewers,
+		RequestedReviewers: pr.RequestedReviewers,
+		AssignedReviewers:  pr.AssignedReviewers,
+		Reviewers:          make([]ReviewerResponse, 0, len(pr.Reviewers)),
+		PendingReviewers:   pr.PendingReviewers,
+		PolicyWarnings:     pr.Warnings,
+		CreatedAt:          pr.CreatedAt,
+		MergedAt:           pr.MergedAt,
+		ClosedAt:           pr.ClosedAt,
+	}
+
+	for _, rv := range pr.Reviewers {
+		res.Reviewers = append(res.Reviewers, mapReviewerToResponse(rv))
+	}
+	for _, rv := range pr.DroppedReviewers {
+		res.DroppedRequestedReviewers = append(res.DroppedRequestedReviewers, InvalidReviewer{UserID: rv.UserID, Reason: rv.Reason})
+	}
+	for _, d := range pr.Declines {
+		res.Declines = append(res.Declines, ReviewDeclineResponse{
+			UserID:     d.UserID,
+			Reason:     d.Reason,
+			ReplacedBy: d.ReplacedBy,
+			DeclinedAt: d.DeclinedAt,
+		})
+	}
+
+	return res
+}
+
+func mapReviewerToResponse(rv storage.ReviewerAssignment) ReviewerResponse {
+	res := ReviewerResponse{
+		UserID:        rv.UserID,
+		Source:        rv.Source,
+		TeamName:      rv.TeamName,
+		MatchedLabels: rv.MatchedLabels,
+		Strategy:      rv.Strategy,
+		Verdict:       rv.Verdict,
+		VerdictAt:     rv.VerdictAt,
+	}
+	if rv.SelectionSeed != nil {
+		res.SelectionSeed = strconv.FormatInt(*rv.SelectionSeed, 10)
+	}
+	if rv.Timing != nil {
+		res.Timing = &ReviewerTimingResponse{
+			AssignedAt:               rv.Timing.AssignedAt,
+			FirstActionAt:            rv.Timing.FirstActionAt,
+			CompletedAt:              rv.Timing.CompletedAt,
+			TimeToFirstActionSeconds: secondsSince(rv.Timing.AssignedAt, rv.Timing.FirstActionAt),
+			TimeToCompleteSeconds:    secondsSince(rv.Timing.AssignedAt, rv.Timing.CompletedAt),
+		}
+	}
+	return res
+}
+
+// secondsSince — секунды между from и to; nil, если одного из моментов нет
+func secondsSince(from, to *time.Time) *int64 {
+	if from == nil || to == nil {
+		return nil
+	}
+	d := int64(to.Sub(*from) / time.Second)
+	return &d
+}
+
+func mapInvalidReviewersToResponse(err *storage.InvalidReviewersError) InvalidReviewersResponse {
+	res := InvalidReviewersResponse{
+		Error: InvalidReviewersBody{
+			Code:      "INVALID_REQUESTED_REVIEWERS",
+			Message:   "some requested reviewers cannot be assigned",
+			Reviewers: make([]InvalidReviewer, 0, len(err.Reviewers)),
+		},
+	}
+	for _, rv := range err.Reviewers {
+		res.Error.Reviewers = append(res.Error.Reviewers, InvalidReviewer{
+			UserID: rv.UserID,
+			Reason: rv.Reason,
+		})
 	}
+	return res
 }
@@ -0,0 +1,183 @@
+package pullrequest
+
+import (
+	"errors"
+	"net/http"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type DeclineRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"` // назначенный ревьювер, который отказывается
+	Reason        string `json:"reason"`  // CONFLICT, NO_EXPERTISE, OVERLOADED
+}
+
+type DeclineResponse struct {
+	PR         PRResponse `json:"pr"`
+	ReplacedBy string     `json:"replaced_by,omitempty"` // пусто — заменить некем, место в очереди
+}
+
+// Handler
+
+// POST /pullRequest/decline
+func Decline(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pullrequest.decline"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req DeclineRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.PullRequestID == "" || req.UserID == "" || req.Reason == "" {
+			log.Warn("missing required fields",
+				slog.String("pull_request_id", req.PullRequestID),
+				slog.String("user_id", req.UserID),
+				slog.String("reason", req.Reason),
+			)
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("pull_request_id, user_id and reason are required"))
+
+			return
+		}
+
+		pr, replacedBy, err := repo.DeclineReview(req.PullRequestID, req.UserID, req.Reason)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrInvalidDeclineReason):
+				log.Info("invalid decline reason", slog.String("reason", req.Reason))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_REASON",
+						Message: "reason must be one of CONFLICT, NO_EXPERTISE, OVERLOADED",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrNotFound):
+				log.Info("pr or user not found for decline",
+					slog.String("pull_request_id", req.PullRequestID),
+					slog.String("user_id", req.UserID),
+				)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrPRMerged):
+				log.Info("attempt to decline review on merged PR",
+					slog.String("pull_request_id", req.PullRequestID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_MERGED",
+						Message: "cannot decline review on merged PR",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrPRClosed):
+				log.Info("attempt to decline review on closed PR",
+					slog.String("pull_request_id", req.PullRequestID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_CLOSED",
+						Message: "cannot decline review on closed PR",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrPRDraft):
+				log.Info("attempt to decline review on draft PR",
+					slog.String("pull_request_id", req.PullRequestID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_DRAFT",
+						Message: "cannot decline review on draft PR",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrNotAssigned):
+				log.Info("user is not assigned reviewer",
+					slog.String("pull_request_id", req.PullRequestID),
+					slog.String("user_id", req.UserID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_ASSIGNED",
+						Message: "reviewer is not assigned to this PR",
+					},
+				})
+
+				return
+
+			default:
+				log.Error("failed to decline review", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("internal error"))
+
+				return
+			}
+		}
+
+		res := DeclineResponse{
+			PR:         mapPullRequestToResponse(pr),
+			ReplacedBy: replacedBy,
+		}
+
+		log.Info("review declined",
+			slog.String("pull_request_id", pr.ID),
+			slog.String("user_id", req.UserID),
+			slog.String("reason", req.Reason),
+			slog.String("replaced_by", replacedBy),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
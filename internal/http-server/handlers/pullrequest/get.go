@@ -0,0 +1,72 @@
+package pullrequest
+
+import (
+	"errors"
+	"net/http"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type GetResponse struct {
+	PR PRResponse `json:"pr"`
+}
+
+// Handler
+
+// GET /pullRequest/get?pull_request_id=...
+func Get(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pullrequest.get"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		prID := r.URL.Query().Get("pull_request_id")
+		if prID == "" {
+			log.Warn("pull_request_id query param is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("pull_request_id is required"))
+
+			return
+		}
+
+		pr, err := repo.GetPullRequest(prID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("pull request not found", slog.String("pull_request_id", prID))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to get pull request", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, GetResponse{PR: mapPullRequestToResponse(pr)})
+	}
+}
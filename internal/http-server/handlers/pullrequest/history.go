@@ -0,0 +1,100 @@
+package pullrequest
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type HistoryResponse struct {
+	PullRequestID string          `json:"pull_request_id"`
+	Events        []EventResponse `json:"events"`
+}
+
+type EventResponse struct {
+	ID            int64     `json:"id"`
+	Type          string    `json:"type"`
+	UserID        string    `json:"user_id,omitempty"`
+	ReplacementID string    `json:"replacement_id,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	Actor         string    `json:"actor,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// Handler
+
+// GET /pullRequest/history?pull_request_id=...
+func History(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pullrequest.history"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		prID := r.URL.Query().Get("pull_request_id")
+		if prID == "" {
+			log.Warn("pull_request_id query param is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("pull_request_id is required"))
+
+			return
+		}
+
+		events, err := repo.GetPullRequestHistory(prID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("pull request not found", slog.String("pull_request_id", prID))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to get pull request history", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		res := HistoryResponse{
+			PullRequestID: prID,
+			Events:        make([]EventResponse, 0, len(events)),
+		}
+		for _, e := range events {
+			res.Events = append(res.Events, EventResponse{
+				ID:            e.ID,
+				Type:          e.Type,
+				UserID:        e.UserID,
+				ReplacementID: e.ReplacementID,
+				Reason:        e.Reason,
+				Actor:         e.Actor,
+				CreatedAt:     e.CreatedAt,
+			})
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
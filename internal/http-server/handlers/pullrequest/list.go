@@ -0,0 +1,132 @@
+package pullrequest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type ListResponse struct {
+	PullRequests []PRResponse `json:"pull_requests"`
+	NextCursor   string       `json:"next_cursor,omitempty"` // пусто — страница последняя
+}
+
+// Handler
+
+// GET /pullRequest/list?status=&author_id=&team_name=&reviewer_id=&created_from=&created_to=&merged_from=&merged_to=&limit=&cursor=
+// Все параметры необязательны; даты — RFC 3339.
+func List(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pullrequest.list"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		filter, err := parseListFilter(r.URL.Query())
+		if err != nil {
+			log.Info("invalid list query", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, ErrorResponse{
+				Error: ErrorBody{
+					Code:    "INVALID_FILTER",
+					Message: err.Error(),
+				},
+			})
+
+			return
+		}
+
+		page, err := repo.ListPullRequests(filter)
+		if err != nil {
+			if errors.Is(err, storage.ErrInvalidFilter) {
+				log.Info("invalid list filter", sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_FILTER",
+						Message: err.Error(),
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to list pull requests", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		res := ListResponse{
+			PullRequests: make([]PRResponse, 0, len(page.PullRequests)),
+			NextCursor:   page.NextCursor,
+		}
+		for _, pr := range page.PullRequests {
+			res.PullRequests = append(res.PullRequests, mapPullRequestToResponse(pr))
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
+
+func parseListFilter(q url.Values) (storage.PullRequestFilter, error) {
+	filter := storage.PullRequestFilter{
+		Status:     q.Get("status"),
+		AuthorID:   q.Get("author_id"),
+		TeamName:   q.Get("team_name"),
+		ReviewerID: q.Get("reviewer_id"),
+		Cursor:     q.Get("cursor"),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return storage.PullRequestFilter{}, errors.New("limit must be an integer")
+		}
+		filter.Limit = limit
+	}
+
+	dates := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+		{"merged_from", &filter.MergedFrom},
+		{"merged_to", &filter.MergedTo},
+	}
+	for _, d := range dates {
+		v := q.Get(d.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return storage.PullRequestFilter{}, fmt.Errorf("%s must be an RFC 3339 timestamp", d.name)
+		}
+		*d.dst = &t
+	}
+
+	return filter, nil
+}
@@ -24,6 +24,24 @@ type MergeResponse struct {
 	PR PRResponse `json:"pr"`
 }
 
+// MergeBlockedResponse — ошибка со списком невыполненных условий merge
+type MergeBlockedResponse struct {
+	Error MergeBlockedBody `json:"error"`
+}
+
+type MergeBlockedBody struct {
+	Code            string           `json:"code"`
+	Message         string           `json:"message"`
+	UnmetConditions []UnmetCondition `json:"unmet_conditions"`
+}
+
+type UnmetCondition struct {
+	Code     string   `json:"code"`               // APPROVALS_REQUIRED, CHANGES_REQUESTED, SENIOR_OR_OWNER_APPROVAL_REQUIRED
+	Required int      `json:"required,omitempty"` // APPROVALS_REQUIRED: сколько нужно одобрений
+	Actual   int      `json:"actual,omitempty"`   // APPROVALS_REQUIRED: сколько есть
+	UserIDs  []string `json:"user_ids,omitempty"` // CHANGES_REQUESTED: кто запросил изменения
+}
+
 // Handler
 
 // POST /pullRequest/merge
@@ -71,6 +89,63 @@ func Merge(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 				return
 			}
 
+			if errors.Is(err, storage.ErrPRDraft) {
+				log.Info("attempt to merge draft PR", slog.String("pull_request_id", req.PullRequestID))
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_DRAFT",
+						Message: "cannot merge draft PR",
+					},
+				})
+
+				return
+			}
+
+			if errors.Is(err, storage.ErrPRClosed) {
+				log.Info("attempt to merge closed PR", slog.String("pull_request_id", req.PullRequestID))
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_CLOSED",
+						Message: "cannot merge closed PR",
+					},
+				})
+
+				return
+			}
+
+			var blocked *storage.MergeBlockedError
+			if errors.As(err, &blocked) {
+				log.Info("merge conditions are not met",
+					slog.String("pull_request_id", req.PullRequestID),
+					slog.Int("unmet", len(blocked.Conditions)),
+				)
+
+				res := MergeBlockedResponse{
+					Error: MergeBlockedBody{
+						Code:            "MERGE_BLOCKED",
+						Message:         "team merge conditions are not met",
+						UnmetConditions: make([]UnmetCondition, 0, len(blocked.Conditions)),
+					},
+				}
+				for _, c := range blocked.Conditions {
+					res.Error.UnmetConditions = append(res.Error.UnmetConditions, UnmetCondition{
+						Code:     c.Code,
+						Required: c.Required,
+						Actual:   c.Actual,
+						UserIDs:  c.UserIDs,
+					})
+				}
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, res)
+
+				return
+			}
+
 			log.Error("failed to merge pull request", sl.Err(err))
 
 			render.Status(r, http.StatusInternalServerError)
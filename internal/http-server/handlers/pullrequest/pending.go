@@ -0,0 +1,93 @@
+package pullrequest
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type PendingResponse struct {
+	PullRequests []PendingPullRequest `json:"pull_requests"`
+}
+
+type PendingPullRequest struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	TeamName          string     `json:"team_name"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	PendingReviewers  int        `json:"pending_reviewers"`
+	CreatedAt         *time.Time `json:"createdAt,omitempty"`
+}
+
+// Handler
+
+// GET /pullRequest/pending?team_name=... (team_name необязателен)
+func Pending(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pullrequest.pending"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		teamName := r.URL.Query().Get("team_name")
+
+		queue, err := repo.GetPendingQueue(teamName)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("team not found", slog.String("team_name", teamName))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to get pending queue", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		res := PendingResponse{
+			PullRequests: make([]PendingPullRequest, 0, len(queue)),
+		}
+
+		for _, p := range queue {
+			res.PullRequests = append(res.PullRequests, PendingPullRequest{
+				PullRequestID:     p.ID,
+				PullRequestName:   p.Name,
+				AuthorID:          p.AuthorID,
+				TeamName:          p.TeamName,
+				AssignedReviewers: p.AssignedReviewers,
+				PendingReviewers:  p.PendingReviewers,
+				CreatedAt:         p.CreatedAt,
+			})
+		}
+
+		log.Info("pending queue fetched", slog.Int("pull_requests_count", len(res.PullRequests)))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
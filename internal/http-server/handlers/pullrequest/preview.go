@@ -0,0 +1,178 @@
+package pullrequest
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+// PreviewRequest — те же параметры выбора, что и у /pullRequest/create, без id и имени PR
+type PreviewRequest struct {
+	AuthorID           string   `json:"author_id"`
+	ChangedFiles       []string `json:"changed_files,omitempty"`
+	Labels             []string `json:"labels,omitempty"`
+	ExcludedReviewers  []string `json:"excluded_reviewers,omitempty"`
+	RequestedReviewers []string `json:"requested_reviewers,omitempty"`
+}
+
+type PreviewResponse struct {
+	Preview PreviewBody `json:"preview"`
+}
+
+type PreviewBody struct {
+	TeamName         string              `json:"team_name"`
+	Strategy         string              `json:"strategy"`
+	SelectionSeed    string              `json:"selection_seed"` // с этим сидом был бы сделан выбор
+	Reviewers        []ReviewerResponse  `json:"reviewers"`      // кого бы назначили
+	PendingReviewers int                 `json:"pending_reviewers,omitempty"`
+	PolicyWarnings   []string            `json:"policy_warnings,omitempty"`
+	Candidates       []PreviewCandidate  `json:"candidates"` // из кого выбирали
+	Excluded         []ExcludedCandidate `json:"excluded"`   // кто не попал в кандидаты
+}
+
+type PreviewCandidate struct {
+	UserID             string   `json:"user_id"`
+	TeamName           string   `json:"team_name"`
+	CodeOwner          bool     `json:"code_owner,omitempty"`
+	MatchedLabels      []string `json:"matched_labels,omitempty"`
+	Seniority          string   `json:"seniority"`
+	OpenReviews        int      `json:"open_reviews"`
+	MaxOpenReviews     int      `json:"max_open_reviews"`
+	AvailableInMinutes int      `json:"available_in_minutes"` // 0 — сейчас рабочее время
+}
+
+type ExcludedCandidate struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Reason   string `json:"reason"` // AUTHOR, EXCLUDED_FOR_PR, EXCLUSION_PAIR, INACTIVE, ABSENT, AT_CAPACITY
+}
+
+// Handler
+
+// POST /pullRequest/previewAssignment
+func Preview(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pullrequest.preview"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req PreviewRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.AuthorID == "" {
+			log.Warn("author_id is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("author_id is required"))
+
+			return
+		}
+
+		preview, err := repo.PreviewAssignment(storage.NewPullRequest{
+			AuthorID:           req.AuthorID,
+			ChangedFiles:       req.ChangedFiles,
+			Labels:             req.Labels,
+			ExcludedReviewers:  req.ExcludedReviewers,
+			RequestedReviewers: req.RequestedReviewers,
+		})
+		if err != nil {
+			var invalid *storage.InvalidReviewersError
+			if errors.As(err, &invalid) {
+				log.Info("invalid requested reviewers", slog.Int("invalid", len(invalid.Reviewers)))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, mapInvalidReviewersToResponse(invalid))
+
+				return
+			}
+
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("author not found", slog.String("author_id", req.AuthorID))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to preview assignment", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		res := PreviewResponse{
+			Preview: PreviewBody{
+				TeamName:         preview.TeamName,
+				Strategy:         preview.Strategy,
+				SelectionSeed:    strconv.FormatInt(preview.SelectionSeed, 10),
+				Reviewers:        make([]ReviewerResponse, 0, len(preview.Reviewers)),
+				PendingReviewers: preview.PendingReviewers,
+				PolicyWarnings:   preview.Warnings,
+				Candidates:       make([]PreviewCandidate, 0, len(preview.Candidates)),
+				Excluded:         make([]ExcludedCandidate, 0, len(preview.Excluded)),
+			},
+		}
+		for _, rv := range preview.Reviewers {
+			res.Preview.Reviewers = append(res.Preview.Reviewers, mapReviewerToResponse(rv))
+		}
+		for _, c := range preview.Candidates {
+			res.Preview.Candidates = append(res.Preview.Candidates, PreviewCandidate{
+				UserID:             c.UserID,
+				TeamName:           c.TeamName,
+				CodeOwner:          c.CodeOwner,
+				MatchedLabels:      c.MatchedLabels,
+				Seniority:          c.Seniority,
+				OpenReviews:        c.OpenReviews,
+				MaxOpenReviews:     c.MaxOpenReviews,
+				AvailableInMinutes: int(math.Ceil(c.AvailableIn.Minutes())),
+			})
+		}
+		for _, c := range preview.Excluded {
+			res.Preview.Excluded = append(res.Preview.Excluded, ExcludedCandidate{
+				UserID:   c.UserID,
+				TeamName: c.TeamName,
+				Reason:   c.Reason,
+			})
+		}
+
+		log.Info("assignment previewed",
+			slog.String("author_id", req.AuthorID),
+			slog.Int("candidates", len(preview.Candidates)),
+			slog.Int("excluded", len(preview.Excluded)),
+			slog.Int("reviewers", len(preview.Reviewers)),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
@@ -0,0 +1,138 @@
+package pullrequest
+
+import (
+	"errors"
+	"net/http"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type ReadyRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type ReadyResponse struct {
+	PR PRResponse `json:"pr"`
+}
+
+// Handler
+
+// POST /pullRequest/ready
+func Ready(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pullrequest.ready"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req ReadyRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.PullRequestID == "" {
+			log.Warn("pull_request_id is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("pull_request_id is required"))
+
+			return
+		}
+
+		pr, err := repo.MarkReadyForReview(req.PullRequestID)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrNotFound):
+				log.Info("pull request not found", slog.String("pull_request_id", req.PullRequestID))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrPRMerged):
+				log.Info("attempt to mark merged PR ready", slog.String("pull_request_id", req.PullRequestID))
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_MERGED",
+						Message: "PR is already merged",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrPRClosed):
+				log.Info("attempt to mark closed PR ready", slog.String("pull_request_id", req.PullRequestID))
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_CLOSED",
+						Message: "PR is closed; reopen it first",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrInvalidTransition):
+				log.Info("attempt to mark non-draft PR ready", slog.String("pull_request_id", req.PullRequestID))
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_TRANSITION",
+						Message: "PR is not a draft",
+					},
+				})
+
+				return
+
+			default:
+				log.Error("failed to mark pull request ready for review", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("internal error"))
+
+				return
+			}
+		}
+
+		res := ReadyResponse{
+			PR: mapPullRequestToResponse(pr),
+		}
+
+		log.Info("pull request ready for review",
+			slog.String("pull_request_id", pr.ID),
+			slog.String("status", pr.Status),
+			slog.Int("pending_reviewers", pr.PendingReviewers),
+			slog.Any("policy_warnings", pr.Warnings),
+			slog.Int("dropped_requested_reviewers", len(pr.DroppedReviewers)),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
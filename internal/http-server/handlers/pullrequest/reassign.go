@@ -19,6 +19,7 @@ import (
 type ReassignRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"` 
+	ActorID       string `json:"actor_id,omitempty"` // кто просит замену; попадает в историю PR
 }
 
 type ReassignResponse struct {
@@ -60,7 +61,7 @@ func Reassign(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 			return
 		}
 
-		pr, replacedBy, err := repo.ReassignReviewer(req.PullRequestID, req.OldUserID)
+		pr, replacedBy, err := repo.ReassignReviewer(req.PullRequestID, req.OldUserID, req.ActorID)
 		if err != nil {
 			switch {
 			case errors.Is(err, storage.ErrNotFound):
@@ -94,6 +95,36 @@ func Reassign(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 
 				return
 
+			case errors.Is(err, storage.ErrPRClosed):
+				log.Info("attempt to reassign on closed PR",
+					slog.String("pull_request_id", req.PullRequestID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_CLOSED",
+						Message: "cannot reassign on closed PR",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrPRDraft):
+				log.Info("attempt to reassign on draft PR",
+					slog.String("pull_request_id", req.PullRequestID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_DRAFT",
+						Message: "cannot reassign on draft PR",
+					},
+				})
+
+				return
+
 			case errors.Is(err, storage.ErrNotAssigned):
 				log.Info("user is not assigned reviewer",
 					slog.String("pull_request_id", req.PullRequestID),
@@ -126,6 +157,22 @@ func Reassign(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 
 				return
 
+			case errors.Is(err, storage.ErrNoSeniorCandidate):
+				log.Info("no senior replacement candidate",
+					slog.String("pull_request_id", req.PullRequestID),
+					slog.String("old_user_id", req.OldUserID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NO_SENIOR_CANDIDATE",
+						Message: "team requires a senior reviewer, but no senior replacement candidate is available",
+					},
+				})
+
+				return
+
 			default:
 				log.Error("failed to reassign reviewer", sl.Err(err))
 
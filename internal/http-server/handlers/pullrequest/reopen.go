@@ -0,0 +1,122 @@
+package pullrequest
+
+import (
+	"errors"
+	"net/http"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type ReopenRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type ReopenResponse struct {
+	PR PRResponse `json:"pr"`
+}
+
+// Handler
+
+// POST /pullRequest/reopen
+func Reopen(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pullrequest.reopen"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req ReopenRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.PullRequestID == "" {
+			log.Warn("pull_request_id is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("pull_request_id is required"))
+
+			return
+		}
+
+		pr, err := repo.ReopenPullRequest(req.PullRequestID)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrNotFound):
+				log.Info("pull request not found", slog.String("pull_request_id", req.PullRequestID))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrPRMerged):
+				log.Info("attempt to reopen merged PR", slog.String("pull_request_id", req.PullRequestID))
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_MERGED",
+						Message: "cannot reopen merged PR",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrInvalidTransition):
+				log.Info("attempt to reopen PR that is not closed", slog.String("pull_request_id", req.PullRequestID))
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_TRANSITION",
+						Message: "PR is not closed",
+					},
+				})
+
+				return
+
+			default:
+				log.Error("failed to reopen pull request", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("internal error"))
+
+				return
+			}
+		}
+
+		res := ReopenResponse{
+			PR: mapPullRequestToResponse(pr),
+		}
+
+		log.Info("pull request reopened",
+			slog.String("pull_request_id", pr.ID),
+			slog.String("status", pr.Status),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
@@ -0,0 +1,180 @@
+package pullrequest
+
+import (
+	"errors"
+	"net/http"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type ReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"` // назначенный ревьювер
+	Verdict       string `json:"verdict"` // APPROVED, CHANGES_REQUESTED, COMMENTED
+}
+
+type ReviewResponse struct {
+	PR PRResponse `json:"pr"`
+}
+
+// Handler
+
+// POST /pullRequest/review
+func Review(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pullrequest.review"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req ReviewRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.PullRequestID == "" || req.UserID == "" || req.Verdict == "" {
+			log.Warn("missing required fields",
+				slog.String("pull_request_id", req.PullRequestID),
+				slog.String("user_id", req.UserID),
+				slog.String("verdict", req.Verdict),
+			)
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("pull_request_id, user_id and verdict are required"))
+
+			return
+		}
+
+		pr, err := repo.SubmitReview(req.PullRequestID, req.UserID, req.Verdict)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrInvalidVerdict):
+				log.Info("invalid verdict", slog.String("verdict", req.Verdict))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_VERDICT",
+						Message: "verdict must be one of APPROVED, CHANGES_REQUESTED, COMMENTED",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrNotFound):
+				log.Info("pr or user not found for review",
+					slog.String("pull_request_id", req.PullRequestID),
+					slog.String("user_id", req.UserID),
+				)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrPRMerged):
+				log.Info("attempt to review merged PR",
+					slog.String("pull_request_id", req.PullRequestID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_MERGED",
+						Message: "cannot review merged PR",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrPRClosed):
+				log.Info("attempt to review closed PR",
+					slog.String("pull_request_id", req.PullRequestID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_CLOSED",
+						Message: "cannot review closed PR",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrPRDraft):
+				log.Info("attempt to review draft PR",
+					slog.String("pull_request_id", req.PullRequestID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "PR_DRAFT",
+						Message: "cannot review draft PR",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrNotAssigned):
+				log.Info("user is not assigned reviewer",
+					slog.String("pull_request_id", req.PullRequestID),
+					slog.String("user_id", req.UserID),
+				)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_ASSIGNED",
+						Message: "reviewer is not assigned to this PR",
+					},
+				})
+
+				return
+
+			default:
+				log.Error("failed to submit review", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("internal error"))
+
+				return
+			}
+		}
+
+		res := ReviewResponse{
+			PR: mapPullRequestToResponse(pr),
+		}
+
+		log.Info("review submitted",
+			slog.String("pull_request_id", pr.ID),
+			slog.String("user_id", req.UserID),
+			slog.String("verdict", req.Verdict),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
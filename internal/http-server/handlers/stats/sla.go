@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO ответа для /stats/sla
+type SLAStatsResponse struct {
+	TeamName   string               `json:"team_name,omitempty"`
+	Total      int                  `json:"total_breaches"`
+	Flagged    int                  `json:"flagged"`
+	Reassigned int                  `json:"reassigned"`
+	ByReviewer []SLAReviewerStatDTO `json:"breaches_by_reviewer"`
+	Breaches   []SLABreachDTO       `json:"breaches"`
+}
+
+type SLAReviewerStatDTO struct {
+	UserID   string `json:"user_id"`
+	Breaches int    `json:"breaches"`
+}
+
+type SLABreachDTO struct {
+	PullRequestID string    `json:"pull_request_id"`
+	UserID        string    `json:"user_id"`
+	TeamName      string    `json:"team_name"`
+	AssignedAt    time.Time `json:"assignedAt"`
+	DetectedAt    time.Time `json:"detectedAt"`
+	Outcome       string    `json:"outcome"` // FLAGGED, REASSIGNED
+	ReplacedBy    string    `json:"replaced_by,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// GET /stats/sla?team_name=...
+func SLA(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.stats.sla"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		teamName := r.URL.Query().Get("team_name")
+
+		stats, err := repo.GetSLAStats(teamName)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("team not found", slog.String("team_name", teamName))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to get sla stats", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		res := SLAStatsResponse{
+			TeamName:   stats.TeamName,
+			Total:      stats.Total,
+			Flagged:    stats.Flagged,
+			Reassigned: stats.Reassigned,
+			ByReviewer: make([]SLAReviewerStatDTO, 0, len(stats.ByReviewer)),
+			Breaches:   make([]SLABreachDTO, 0, len(stats.Breaches)),
+		}
+		for _, st := range stats.ByReviewer {
+			res.ByReviewer = append(res.ByReviewer, SLAReviewerStatDTO{
+				UserID:   st.UserID,
+				Breaches: st.Breaches,
+			})
+		}
+		for _, b := range stats.Breaches {
+			res.Breaches = append(res.Breaches, SLABreachDTO{
+				PullRequestID: b.PullRequestID,
+				UserID:        b.UserID,
+				TeamName:      b.TeamName,
+				AssignedAt:    b.AssignedAt,
+				DetectedAt:    b.DetectedAt,
+				Outcome:       b.Outcome,
+				ReplacedBy:    b.ReplacedBy,
+			})
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
@@ -17,20 +17,22 @@ import (
 type AddRequest struct {
 	TeamName string `json:"team_name" validate:"required"`
 	Members  []struct {
-		UserID   string `json:"user_id" validate:"required"`
-		Username string `json:"username" validate:"required"`
-		IsActive bool   `json:"is_active"`
+		UserID         string   `json:"user_id" validate:"required"`
+		Username       string   `json:"username" validate:"required"`
+		IsActive       bool     `json:"is_active"`
+		MaxOpenReviews *int     `json:"max_open_reviews,omitempty"` // не указан — у существующего пользователя не меняется
+		Skills         []string `json:"skills,omitempty"`
+		Seniority      string   `json:"seniority,omitempty"`  // junior, middle, senior
+		Timezone       string   `json:"timezone,omitempty"`   // IANA, например Europe/Moscow
+		WorkStart      string   `json:"work_start,omitempty"` // HH:MM, рабочие часы пн–пт
+		WorkEnd        string   `json:"work_end,omitempty"`
 	} `json:"members"`
 }
 
 type AddResponse struct {
 	Team struct {
-		TeamName string `json:"team_name"`
-		Members  []struct {
-			UserID   string `json:"user_id"`
-			Username string `json:"username"`
-			IsActive bool   `json:"is_active"`
-		} `json:"members"`
+		TeamName string          `json:"team_name"`
+		Members  []GetTeamMember `json:"members"`
 	} `json:"team"`
 }
 
@@ -52,10 +54,29 @@ func Add(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 
 		members := make([]storage.TeamMember, 0, len(req.Members))
 		for _, m := range req.Members {
+			if m.MaxOpenReviews != nil && *m.MaxOpenReviews < 0 {
+				log.Warn("negative max_open_reviews", slog.String("user_id", m.UserID), slog.Int("max_open_reviews", *m.MaxOpenReviews))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("max_open_reviews must be non-negative (0 means unlimited)"))
+				return
+			}
+			if m.Seniority != "" && !storage.ValidSeniority(m.Seniority) {
+				log.Warn("invalid seniority", slog.String("user_id", m.UserID), slog.String("seniority", m.Seniority))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("seniority must be one of junior, middle, senior"))
+				return
+			}
+
 			members = append(members, storage.TeamMember{
-				UserID:   m.UserID,
-				Username: m.Username,
-				IsActive: m.IsActive,
+				UserID:         m.UserID,
+				Username:       m.Username,
+				IsActive:       m.IsActive,
+				MaxOpenReviews: m.MaxOpenReviews,
+				Skills:         m.Skills,
+				Seniority:      m.Seniority,
+				Timezone:       m.Timezone,
+				WorkStart:      m.WorkStart,
+				WorkEnd:        m.WorkEnd,
 			})
 		}
 
@@ -71,6 +92,16 @@ func Add(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 				})
 				return
 			}
+			if errors.Is(err, storage.ErrInvalidWorkHours) {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_WORK_HOURS",
+						Message: err.Error(),
+					},
+				})
+				return
+			}
 			log.Error("failed to create team", sl.Err(err))
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, resp.Error("internal error"))
@@ -80,15 +111,7 @@ func Add(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 		var res AddResponse
 		res.Team.TeamName = team.TeamName
 		for _, m := range team.Members {
-			res.Team.Members = append(res.Team.Members, struct {
-				UserID   string `json:"user_id"`
-				Username string `json:"username"`
-				IsActive bool   `json:"is_active"`
-			}{
-				UserID:   m.UserID,
-				Username: m.Username,
-				IsActive: m.IsActive,
-			})
+			res.Team.Members = append(res.Team.Members, mapMemberToResponse(m))
 		}
 
 		render.Status(r, http.StatusCreated)
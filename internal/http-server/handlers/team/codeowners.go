@@ -0,0 +1,168 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type SetCodeOwnersRequest struct {
+	TeamName string `json:"team_name"`
+	Content  string `json:"content"` // файл в синтаксисе GitHub CODEOWNERS, владельцы — @user_id
+}
+
+type CodeOwnersResponse struct {
+	TeamName   string     `json:"team_name"`
+	Content    string     `json:"content"`
+	RulesCount int        `json:"rules_count"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Handlers
+
+// GET /team/codeowners?team_name=...
+func GetCodeOwners(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.team.get_codeowners"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		teamName := r.URL.Query().Get("team_name")
+		if teamName == "" {
+			log.Warn("team_name query param is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("team_name is required"))
+
+			return
+		}
+
+		co, err := repo.GetTeamCodeOwners(teamName)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("team or codeowners not found", slog.String("team_name", teamName))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to get codeowners", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, mapCodeOwnersToResponse(co))
+	}
+}
+
+// POST /team/codeowners
+func SetCodeOwners(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.team.set_codeowners"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req SetCodeOwnersRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.TeamName == "" {
+			log.Warn("team_name is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("team_name is required"))
+
+			return
+		}
+
+		co, err := repo.SetTeamCodeOwners(req.TeamName, req.Content)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrNotFound):
+				log.Info("team not found", slog.String("team_name", req.TeamName))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrInvalidCodeOwners):
+				log.Info("invalid codeowners", slog.String("team_name", req.TeamName), sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_CODEOWNERS",
+						Message: err.Error(),
+					},
+				})
+
+				return
+
+			default:
+				log.Error("failed to set codeowners", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("internal error"))
+
+				return
+			}
+		}
+
+		log.Info("codeowners updated",
+			slog.String("team_name", co.TeamName),
+			slog.Int("rules_count", co.RulesCount),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, mapCodeOwnersToResponse(co))
+	}
+}
+
+func mapCodeOwnersToResponse(co storage.TeamCodeOwners) CodeOwnersResponse {
+	return CodeOwnersResponse{
+		TeamName:   co.TeamName,
+		Content:    co.Content,
+		RulesCount: co.RulesCount,
+		UpdatedAt:  co.UpdatedAt,
+	}
+}
@@ -23,6 +23,7 @@ type DeactivateUsersResponse struct {
 	DeactivatedUserIDs []string `json:"deactivated_user_ids"`
 	ReassignedCount    int      `json:"reassigned_reviewers"`
 	RemovedCount       int      `json:"removed_reviewers"`
+	SeniorUnavailable  []string `json:"senior_unavailable"` // PR, которым не нашлось senior'а на замену
 }
 
 func DeactivateUsers(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
@@ -71,6 +72,7 @@ func DeactivateUsers(log *slog.Logger, repo storage.Repository) http.HandlerFunc
 			DeactivatedUserIDs: resBulk.DeactivatedUserIDs,
 			ReassignedCount:    resBulk.ReassignedCount,
 			RemovedCount:       resBulk.RemovedAssignments,
+			SeniorUnavailable:  resBulk.SeniorUnavailable,
 		}
 
 		render.Status(r, http.StatusOK)
@@ -15,9 +15,15 @@ import (
 )
 
 type GetTeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID         string   `json:"user_id"`
+	Username       string   `json:"username"`
+	IsActive       bool     `json:"is_active"`
+	MaxOpenReviews int      `json:"max_open_reviews"`
+	Skills         []string `json:"skills"`
+	Seniority      string   `json:"seniority"`
+	Timezone       string   `json:"timezone,omitempty"`
+	WorkStart      string   `json:"work_start,omitempty"`
+	WorkEnd        string   `json:"work_end,omitempty"`
 }
 
 type GetResponse struct {
@@ -84,11 +90,7 @@ func Get(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 		}
 
 		for _, m := range team.Members {
-			res.Members = append(res.Members, GetTeamMember{
-				UserID:   m.UserID,
-				Username: m.Username,
-				IsActive: m.IsActive,
-			})
+			res.Members = append(res.Members, mapMemberToResponse(m))
 		}
 
 		log.Info("team fetched", slog.String("team_name", team.TeamName))
@@ -97,3 +99,20 @@ func Get(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 		render.JSON(w, r, res)
 	}
 }
+
+func mapMemberToResponse(m storage.TeamMember) GetTeamMember {
+	res := GetTeamMember{
+		UserID:    m.UserID,
+		Username:  m.Username,
+		IsActive:  m.IsActive,
+		Skills:    m.Skills,
+		Seniority: m.Seniority,
+		Timezone:  m.Timezone,
+		WorkStart: m.WorkStart,
+		WorkEnd:   m.WorkEnd,
+	}
+	if m.MaxOpenReviews != nil {
+		res.MaxOpenReviews = *m.MaxOpenReviews
+	}
+	return res
+}
@@ -0,0 +1,135 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type RebalanceRequest struct {
+	TeamName  string `json:"team_name"`
+	Tolerance *int   `json:"tolerance,omitempty"` // допустимая разница открытых ревью; нет — из конфига
+	DryRun    bool   `json:"dry_run,omitempty"`   // только план, без изменений
+}
+
+type RebalanceResponse struct {
+	TeamName     string          `json:"team_name"`
+	Tolerance    int             `json:"tolerance"`
+	DryRun       bool            `json:"dry_run"`
+	SpreadBefore int             `json:"spread_before"`
+	SpreadAfter  int             `json:"spread_after"`
+	Moves        []RebalanceMove `json:"moves"`
+}
+
+type RebalanceMove struct {
+	PullRequestID string `json:"pull_request_id"`
+	FromUserID    string `json:"from_user_id"`
+	ToUserID      string `json:"to_user_id"`
+}
+
+// Handler
+
+// POST /team/rebalance
+func Rebalance(log *slog.Logger, repo storage.Repository, defaultTolerance int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.team.rebalance"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req RebalanceRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.TeamName == "" {
+			log.Warn("team_name is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("team_name is required"))
+
+			return
+		}
+
+		tolerance := defaultTolerance
+		if req.Tolerance != nil {
+			tolerance = *req.Tolerance
+		}
+		if tolerance < 0 {
+			log.Warn("negative tolerance", slog.Int("tolerance", tolerance))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("tolerance must be non-negative"))
+
+			return
+		}
+
+		result, err := repo.RebalanceTeam(req.TeamName, tolerance, req.DryRun)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("team not found", slog.String("team_name", req.TeamName))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to rebalance team", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		res := RebalanceResponse{
+			TeamName:     result.TeamName,
+			Tolerance:    result.Tolerance,
+			DryRun:       result.DryRun,
+			SpreadBefore: result.SpreadBefore,
+			SpreadAfter:  result.SpreadAfter,
+			Moves:        make([]RebalanceMove, 0, len(result.Moves)),
+		}
+		for _, m := range result.Moves {
+			res.Moves = append(res.Moves, RebalanceMove{
+				PullRequestID: m.PullRequestID,
+				FromUserID:    m.FromUserID,
+				ToUserID:      m.ToUserID,
+			})
+		}
+
+		log.Info("team rebalanced",
+			slog.String("team_name", result.TeamName),
+			slog.Bool("dry_run", result.DryRun),
+			slog.Int("moves", len(result.Moves)),
+			slog.Int("spread_before", result.SpreadBefore),
+			slog.Int("spread_after", result.SpreadAfter),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
@@ -0,0 +1,226 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type UpdateSettingsRequest struct {
+	TeamName               string    `json:"team_name"`
+	ReviewerCount          *int      `json:"reviewer_count,omitempty"`
+	Strategy               *string   `json:"strategy,omitempty"`
+	AllowCrossTeamFallback *bool     `json:"allow_cross_team_fallback,omitempty"`
+	FallbackTeams          *[]string `json:"fallback_teams,omitempty"`
+	RequireSenior          *bool     `json:"require_senior,omitempty"`
+	// условия merge
+	RequiredApprovals            *int  `json:"required_approvals,omitempty"`
+	BlockOnChangesRequested      *bool `json:"block_on_changes_requested,omitempty"`
+	RequireSeniorOrOwnerApproval *bool `json:"require_senior_or_owner_approval,omitempty"`
+	// SLA ревью: рабочее время ревьювера в формате Go duration ("24h"; "0s" — выключить)
+	ReviewSLA       *string `json:"review_sla,omitempty"`
+	ReviewSLAAction *string `json:"review_sla_action,omitempty"` // FLAG, REASSIGN
+}
+
+type SettingsResponse struct {
+	TeamName                     string   `json:"team_name"`
+	ReviewerCount                int      `json:"reviewer_count"`
+	Strategy                     string   `json:"strategy"`
+	EffectiveStrategy            string   `json:"effective_strategy"`
+	AllowCrossTeamFallback       bool     `json:"allow_cross_team_fallback"`
+	FallbackTeams                []string `json:"fallback_teams"`
+	RequireSenior                bool     `json:"require_senior"`
+	RequiredApprovals            int      `json:"required_approvals"`
+	BlockOnChangesRequested      bool     `json:"block_on_changes_requested"`
+	RequireSeniorOrOwnerApproval bool     `json:"require_senior_or_owner_approval"`
+	ReviewSLA                    string   `json:"review_sla"`
+	ReviewSLAAction              string   `json:"review_sla_action"`
+}
+
+// Handlers
+
+// GET /team/settings?team_name=...
+func GetSettings(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.team.get_settings"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		teamName := r.URL.Query().Get("team_name")
+		if teamName == "" {
+			log.Warn("team_name query param is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("team_name is required"))
+
+			return
+		}
+
+		settings, err := repo.GetTeamSettings(teamName)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("team not found", slog.String("team_name", teamName))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to get team settings", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, mapSettingsToResponse(settings))
+	}
+}
+
+// POST /team/settings
+func UpdateSettings(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.team.update_settings"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req UpdateSettingsRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.TeamName == "" {
+			log.Warn("team_name is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("team_name is required"))
+
+			return
+		}
+
+		var reviewSLA *time.Duration
+		if req.ReviewSLA != nil {
+			d, err := time.ParseDuration(*req.ReviewSLA)
+			if err != nil {
+				log.Info("invalid review_sla", slog.String("review_sla", *req.ReviewSLA))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_SETTINGS",
+						Message: "review_sla must be a duration like 24h",
+					},
+				})
+
+				return
+			}
+			reviewSLA = &d
+		}
+
+		settings, err := repo.UpdateTeamSettings(req.TeamName, storage.TeamSettingsUpdate{
+			ReviewerCount:                req.ReviewerCount,
+			Strategy:                     req.Strategy,
+			AllowCrossTeamFallback:       req.AllowCrossTeamFallback,
+			FallbackTeams:                req.FallbackTeams,
+			RequireSenior:                req.RequireSenior,
+			RequiredApprovals:            req.RequiredApprovals,
+			BlockOnChangesRequested:      req.BlockOnChangesRequested,
+			RequireSeniorOrOwnerApproval: req.RequireSeniorOrOwnerApproval,
+			ReviewSLA:                    reviewSLA,
+			ReviewSLAAction:              req.ReviewSLAAction,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrNotFound):
+				log.Info("team not found", slog.String("team_name", req.TeamName))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrInvalidSettings):
+				log.Info("invalid team settings", slog.String("team_name", req.TeamName), sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_SETTINGS",
+						Message: err.Error(),
+					},
+				})
+
+				return
+
+			default:
+				log.Error("failed to update team settings", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("internal error"))
+
+				return
+			}
+		}
+
+		log.Info("team settings updated",
+			slog.String("team_name", settings.TeamName),
+			slog.Int("reviewer_count", settings.ReviewerCount),
+			slog.String("strategy", settings.EffectiveStrategy),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, mapSettingsToResponse(settings))
+	}
+}
+
+func mapSettingsToResponse(s storage.TeamSettings) SettingsResponse {
+	return SettingsResponse{
+		TeamName:                     s.TeamName,
+		ReviewerCount:                s.ReviewerCount,
+		Strategy:                     s.Strategy,
+		EffectiveStrategy:            s.EffectiveStrategy,
+		AllowCrossTeamFallback:       s.AllowCrossTeamFallback,
+		FallbackTeams:                s.FallbackTeams,
+		RequireSenior:                s.RequireSenior,
+		RequiredApprovals:            s.MergePolicy.RequiredApprovals,
+		BlockOnChangesRequested:      s.MergePolicy.BlockOnChangesRequested,
+		RequireSeniorOrOwnerApproval: s.MergePolicy.RequireSeniorOrOwnerApproval,
+		ReviewSLA:                    s.ReviewSLA.Duration.String(),
+		ReviewSLAAction:              s.ReviewSLA.Action,
+	}
+}
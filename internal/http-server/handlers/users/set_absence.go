@@ -0,0 +1,109 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type SetAbsenceRequest struct {
+	UserID string    `json:"user_id"`
+	Start  time.Time `json:"start"` // RFC 3339
+	End    time.Time `json:"end"`
+}
+
+// Handler
+
+// POST /users/setAbsence
+func SetAbsence(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.users.set_absence"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req SetAbsenceRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.UserID == "" || req.Start.IsZero() || req.End.IsZero() {
+			log.Warn("missing required fields", slog.String("user_id", req.UserID))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("user_id, start and end are required"))
+
+			return
+		}
+
+		user, err := repo.SetUserAbsence(req.UserID, req.Start, req.End)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrNotFound):
+				log.Info("user not found", slog.String("user_id", req.UserID))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrInvalidAbsence):
+				log.Info("invalid absence", slog.String("user_id", req.UserID), sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_ABSENCE",
+						Message: err.Error(),
+					},
+				})
+
+				return
+
+			default:
+				log.Error("failed to set user absence", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("internal error"))
+
+				return
+			}
+		}
+
+		res := SetIsActiveResponse{
+			User: mapUserToResponse(user),
+		}
+
+		log.Info("user absence scheduled",
+			slog.String("user_id", user.UserID),
+			slog.Time("start", req.Start),
+			slog.Time("end", req.End),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
@@ -3,6 +3,7 @@ package users
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"log/slog"
 
@@ -26,10 +27,25 @@ type SetIsActiveResponse struct {
 }
 
 type SetIsActiveUser struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID         string   `json:"user_id"`
+	Username       string   `json:"username"`
+	TeamName       string   `json:"team_name"`
+	IsActive       bool     `json:"is_active"`
+	MaxOpenReviews int      `json:"max_open_reviews"`
+	Skills         []string `json:"skills"`
+	Seniority      string   `json:"seniority"`
+	Timezone       string   `json:"timezone,omitempty"`
+	WorkStart      string   `json:"work_start,omitempty"`
+	WorkEnd        string   `json:"work_end,omitempty"`
+	QuietStart     string   `json:"quiet_start,omitempty"`
+	QuietEnd       string   `json:"quiet_end,omitempty"`
+	Absence        *Absence `json:"absence,omitempty"`
+}
+
+type Absence struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	HandedOver bool      `json:"handed_over"` // отсутствие началось, ревью переданы другим
 }
 
 type ErrorResponse struct {
@@ -97,12 +113,7 @@ func SetIsActive(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 		}
 
 		res := SetIsActiveResponse{
-			User: SetIsActiveUser{
-				UserID:   user.UserID,
-				Username: user.Username,
-				TeamName: user.TeamName,
-				IsActive: user.IsActive,
-			},
+			User: mapUserToResponse(user),
 		}
 
 		log.Info("user activity updated",
@@ -114,3 +125,28 @@ func SetIsActive(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
 		render.JSON(w, r, res)
 	}
 }
+
+func mapUserToResponse(user storage.User) SetIsActiveUser {
+	res := SetIsActiveUser{
+		UserID:         user.UserID,
+		Username:       user.Username,
+		TeamName:       user.TeamName,
+		IsActive:       user.IsActive,
+		MaxOpenReviews: user.MaxOpenReviews,
+		Skills:         user.Skills,
+		Seniority:      user.Seniority,
+		Timezone:       user.Timezone,
+		WorkStart:      user.WorkStart,
+		WorkEnd:        user.WorkEnd,
+		QuietStart:     user.QuietStart,
+		QuietEnd:       user.QuietEnd,
+	}
+	if user.Absence != nil {
+		res.Absence = &Absence{
+			Start:      user.Absence.Start,
+			End:        user.Absence.End,
+			HandedOver: user.Absence.HandedOver,
+		}
+	}
+	return res
+}
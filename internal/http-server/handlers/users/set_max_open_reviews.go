@@ -0,0 +1,100 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type SetMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+// Handler
+
+// POST /users/setMaxOpenReviews
+func SetMaxOpenReviews(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.users.set_max_open_reviews"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req SetMaxOpenReviewsRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.UserID == "" || req.MaxOpenReviews == nil {
+			log.Warn("missing required fields", slog.String("user_id", req.UserID))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("user_id and max_open_reviews are required"))
+
+			return
+		}
+
+		if *req.MaxOpenReviews < 0 {
+			log.Warn("negative max_open_reviews", slog.Int("max_open_reviews", *req.MaxOpenReviews))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("max_open_reviews must be non-negative (0 means unlimited)"))
+
+			return
+		}
+
+		user, err := repo.SetUserMaxOpenReviews(req.UserID, *req.MaxOpenReviews)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Info("user not found", slog.String("user_id", req.UserID))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+			}
+
+			log.Error("failed to set user max open reviews", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("internal error"))
+
+			return
+		}
+
+		res := SetIsActiveResponse{
+			User: mapUserToResponse(user),
+		}
+
+		log.Info("user capacity updated",
+			slog.String("user_id", user.UserID),
+			slog.Int("max_open_reviews", user.MaxOpenReviews),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
@@ -0,0 +1,154 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+
+	"log/slog"
+
+	resp "pr-service/internal/lib/api/response"
+	"pr-service/internal/lib/logger/sl"
+	"pr-service/internal/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// DTO
+
+type UpdateUserRequest struct {
+	UserID         string    `json:"user_id"`
+	Username       *string   `json:"username,omitempty"`
+	IsActive       *bool     `json:"is_active,omitempty"`
+	MaxOpenReviews *int      `json:"max_open_reviews,omitempty"`
+	Skills         *[]string `json:"skills,omitempty"`
+	Seniority      *string   `json:"seniority,omitempty"` // junior, middle, senior
+	Timezone       *string   `json:"timezone,omitempty"`
+	WorkStart      *string   `json:"work_start,omitempty"` // "" вместе с пустым work_end снимает расписание
+	WorkEnd        *string   `json:"work_end,omitempty"`
+	QuietStart     *string   `json:"quiet_start,omitempty"` // HH:MM в timezone; окно может переходить через полночь
+	QuietEnd       *string   `json:"quiet_end,omitempty"`
+}
+
+// Handler
+
+// POST /users/update
+func Update(log *slog.Logger, repo storage.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.users.update"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req UpdateUserRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid request body"))
+
+			return
+		}
+
+		if req.UserID == "" {
+			log.Warn("user_id is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("user_id is required"))
+
+			return
+		}
+
+		if req.MaxOpenReviews != nil && *req.MaxOpenReviews < 0 {
+			log.Warn("negative max_open_reviews", slog.Int("max_open_reviews", *req.MaxOpenReviews))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("max_open_reviews must be non-negative (0 means unlimited)"))
+
+			return
+		}
+
+		if req.Seniority != nil && !storage.ValidSeniority(*req.Seniority) {
+			log.Warn("invalid seniority", slog.String("seniority", *req.Seniority))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("seniority must be one of junior, middle, senior"))
+
+			return
+		}
+
+		user, err := repo.UpdateUser(req.UserID, storage.UserUpdate{
+			Username:       req.Username,
+			IsActive:       req.IsActive,
+			MaxOpenReviews: req.MaxOpenReviews,
+			Skills:         req.Skills,
+			Seniority:      req.Seniority,
+			Timezone:       req.Timezone,
+			WorkStart:      req.WorkStart,
+			WorkEnd:        req.WorkEnd,
+			QuietStart:     req.QuietStart,
+			QuietEnd:       req.QuietEnd,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrNotFound):
+				log.Info("user not found", slog.String("user_id", req.UserID))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "NOT_FOUND",
+						Message: "resource not found",
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrInvalidWorkHours):
+				log.Info("invalid working hours", slog.String("user_id", req.UserID), sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_WORK_HOURS",
+						Message: err.Error(),
+					},
+				})
+
+				return
+
+			case errors.Is(err, storage.ErrInvalidQuietHours):
+				log.Info("invalid quiet hours", slog.String("user_id", req.UserID), sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrorResponse{
+					Error: ErrorBody{
+						Code:    "INVALID_QUIET_HOURS",
+						Message: err.Error(),
+					},
+				})
+
+				return
+
+			default:
+				log.Error("failed to update user", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("internal error"))
+
+				return
+			}
+		}
+
+		res := SetIsActiveResponse{
+			User: mapUserToResponse(user),
+		}
+
+		log.Info("user updated", slog.String("user_id", user.UserID))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, res)
+	}
+}
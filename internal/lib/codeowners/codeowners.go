@@ -0,0 +1,157 @@
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var commentStart = regexp.MustCompile(`\s#`)
+
+// Rule — строка CODEOWNERS: шаблон пути и его владельцы (user_id без '@')
+type Rule struct {
+	Pattern string
+	Owners  []string
+	re      *regexp.Regexp
+}
+
+// Ruleset — набор правил в порядке файла; действует последнее подходящее правило
+type Ruleset struct {
+	Rules []Rule
+}
+
+// Parse разбирает файл в синтаксисе GitHub CODEOWNERS.
+// Владельцы вида @user_id сохраняются как user_id; команды (@org/team) и email пропускаются,
+// т.к. в сервисе пользователей знаем только по user_id.
+func Parse(content string) (Ruleset, error) {
+	var rs Ruleset
+
+	sc := bufio.NewScanner(strings.NewReader(content))
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// комментарий в конце строки: '#' после любого пробельного символа
+		if i := commentStart.FindStringIndex(line); i != nil {
+			line = strings.TrimSpace(line[:i[0]])
+		}
+
+		fields := strings.Fields(line)
+		pattern := fields[0]
+
+		re, err := compile(pattern)
+		if err != nil {
+			return Ruleset{}, fmt.Errorf("line %d: invalid pattern %q: %w", lineNo, pattern, err)
+		}
+
+		owners := make([]string, 0, len(fields)-1)
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "@") {
+				continue // email
+			}
+			owner := strings.TrimPrefix(f, "@")
+			if owner == "" || strings.Contains(owner, "/") {
+				continue // @org/team
+			}
+			owners = append(owners, owner)
+		}
+
+		rs.Rules = append(rs.Rules, Rule{Pattern: pattern, Owners: owners, re: re})
+	}
+	if err := sc.Err(); err != nil {
+		return Ruleset{}, err
+	}
+
+	return rs, nil
+}
+
+// Owners возвращает владельцев пути по последнему подходящему правилу
+func (rs Ruleset) Owners(path string) []string {
+	path = normalize(path)
+	for i := len(rs.Rules) - 1; i >= 0; i-- {
+		if rs.Rules[i].re.MatchString(path) {
+			return rs.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// OwnersOf — объединение владельцев всех путей (без повторов, в порядке появления)
+func (rs Ruleset) OwnersOf(paths []string) []string {
+	seen := make(map[string]struct{})
+	var owners []string
+	for _, p := range paths {
+		for _, o := range rs.Owners(p) {
+			if _, ok := seen[o]; ok {
+				continue
+			}
+			seen[o] = struct{}{}
+			owners = append(owners, o)
+		}
+	}
+	return owners
+}
+
+func normalize(path string) string {
+	path = strings.TrimPrefix(path, "./")
+	return strings.TrimPrefix(path, "/")
+}
+
+// compile переводит gitignore-подобный шаблон в регулярное выражение
+func compile(pattern string) (*regexp.Regexp, error) {
+	p := pattern
+
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+
+	// слэш в начале или в середине привязывает шаблон к корню репозитория
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	if p == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(p[i])))
+		}
+	}
+
+	// шаблон совпадает и с самим путём, и со всем, что лежит внутри каталога;
+	// последний сегмент с '*' или '?' (docs/*, *.go) — только с путями на этом уровне
+	last := p[strings.LastIndex(p, "/")+1:]
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.ContainsAny(last, "*?"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,82 @@
+package codeowners
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOwners(t *testing.T) {
+	rs, err := Parse(`
+# владельцы по умолчанию
+*           @lead
+*.sql       @dba
+docs/*      @writer
+/build/logs @ops
+apps/       @apps	# таб перед комментарием
+/src/**/test @qa
+`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"main.go", []string{"lead"}},
+		{"db/migrations/001.sql", []string{"dba"}},
+		{"docs/getting-started.md", []string{"writer"}},
+		{"docs/build-app/troubleshooting.md", []string{"lead"}},
+		{"build/logs/app.log", []string{"ops"}},
+		{"build/logs/2024/app.log", []string{"ops"}},
+		{"tools/build/logs/app.log", []string{"lead"}},
+		{"apps/web/index.js", []string{"apps"}},
+		{"services/apps/api.go", []string{"apps"}},
+		{"src/test/a_test.go", []string{"qa"}},
+		{"src/pkg/x/test/a_test.go", []string{"qa"}},
+		{"./docs/readme.md", []string{"writer"}},
+	}
+	for _, tt := range tests {
+		if got := rs.Owners(tt.path); !slices.Equal(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	rs, err := Parse("db/ @alice #  ревью схемы\nweb/ @bob\t#@carol\n# db/ @dave\n")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if len(rs.Rules) != 2 {
+		t.Fatalf("rules = %d, want 2", len(rs.Rules))
+	}
+	if got := rs.Rules[0].Owners; !slices.Equal(got, []string{"alice"}) {
+		t.Errorf("db/ owners = %v, want [alice]", got)
+	}
+	if got := rs.Rules[1].Owners; !slices.Equal(got, []string{"bob"}) {
+		t.Errorf("web/ owners = %v, want [bob]", got)
+	}
+}
+
+func TestParseSkipsTeamsAndEmails(t *testing.T) {
+	rs, err := Parse("*.go @org/backend dev@example.com @alice\n")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := rs.Owners("cmd/main.go"); !slices.Equal(got, []string{"alice"}) {
+		t.Errorf("owners = %v, want [alice]", got)
+	}
+}
+
+func TestOwnersOf(t *testing.T) {
+	rs, err := Parse("*.go @alice @bob\n*.sql @bob @carol\n")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got := rs.OwnersOf([]string{"a.go", "b.sql", "README.md"})
+	if want := []string{"alice", "bob", "carol"}; !slices.Equal(got, want) {
+		t.Errorf("OwnersOf = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+package workhours
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	// в образе debian-slim нет базы часовых поясов — встраиваем её в бинарник
+	_ "time/tzdata"
+)
+
+// Schedule — рабочие часы пользователя по будням (пн–пт) в его часовом поясе.
+// Нулевое значение — расписание не задано, пользователь считается доступным всегда.
+type Schedule struct {
+	loc        *time.Location
+	start, end int // минуты от полуночи
+}
+
+// Parse собирает расписание. tz — имя из базы IANA (пусто — UTC),
+// start и end — "HH:MM"; оба пустые — расписания нет.
+func Parse(tz, start, end string) (Schedule, error) {
+	if start == "" && end == "" {
+		if tz != "" {
+			if _, err := time.LoadLocation(tz); err != nil {
+				return Schedule{}, fmt.Errorf("unknown timezone %q", tz)
+			}
+		}
+		return Schedule{}, nil
+	}
+	if start == "" || end == "" {
+		return Schedule{}, errors.New("work_start and work_end must be set together")
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("unknown timezone %q", tz)
+	}
+
+	from, err := parseClock(start)
+	if err != nil {
+		return Schedule{}, err
+	}
+	to, err := parseClock(end)
+	if err != nil {
+		return Schedule{}, err
+	}
+	if from >= to {
+		return Schedule{}, errors.New("work_start must be before work_end")
+	}
+
+	return Schedule{loc: loc, start: from, end: to}, nil
+}
+
+// Until — через сколько начнётся рабочее время; 0 — сейчас рабочее время или расписания нет
+func (s Schedule) Until(now time.Time) time.Duration {
+	if s.loc == nil {
+		return 0
+	}
+
+	local := now.In(s.loc)
+	// ближайший будний день с окном позже now найдётся максимум через 3 дня (пятница вечер -> понедельник)
+	for day := 0; day <= 3; day++ {
+		d := local.AddDate(0, 0, day)
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+
+		from := time.Date(d.Year(), d.Month(), d.Day(), s.start/60, s.start%60, 0, 0, s.loc)
+		to := time.Date(d.Year(), d.Month(), d.Day(), s.end/60, s.end%60, 0, 0, s.loc)
+
+		if !local.Before(from) && local.Before(to) {
+			return 0
+		}
+		if local.Before(from) {
+			return from.Sub(local)
+		}
+	}
+
+	return 0
+}
+
+// Between — сколько рабочего времени прошло между from и to; без расписания — всё время
+func (s Schedule) Between(from, to time.Time) time.Duration {
+	if !from.Before(to) {
+		return 0
+	}
+	if s.loc == nil {
+		return to.Sub(from)
+	}
+
+	var total time.Duration
+	local := from.In(s.loc)
+	for d := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.loc); d.Before(to); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+
+		lo := time.Date(d.Year(), d.Month(), d.Day(), s.start/60, s.start%60, 0, 0, s.loc)
+		hi := time.Date(d.Year(), d.Month(), d.Day(), s.end/60, s.end%60, 0, 0, s.loc)
+		if lo.Before(from) {
+			lo = from
+		}
+		if hi.After(to) {
+			hi = to
+		}
+		if lo.Before(hi) {
+			total += hi.Sub(lo)
+		}
+	}
+
+	return total
+}
+
+// Quiet — тихие часы пользователя (каждый день) в его часовом поясе; окно может переходить через полночь.
+// Нулевое значение — тихих часов нет.
+type Quiet struct {
+	loc        *time.Location
+	start, end int // минуты от полуночи
+}
+
+// ParseQuiet собирает тихие часы. tz — имя из базы IANA (пусто — UTC),
+// start и end — "HH:MM"; оба пустые — тихих часов нет.
+func ParseQuiet(tz, start, end string) (Quiet, error) {
+	if start == "" && end == "" {
+		return Quiet{}, nil
+	}
+	if start == "" || end == "" {
+		return Quiet{}, errors.New("quiet_start and quiet_end must be set together")
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return Quiet{}, fmt.Errorf("unknown timezone %q", tz)
+	}
+
+	from, err := parseClock(start)
+	if err != nil {
+		return Quiet{}, err
+	}
+	to, err := parseClock(end)
+	if err != nil {
+		return Quiet{}, err
+	}
+	if from == to {
+		return Quiet{}, errors.New("quiet_start and quiet_end must differ")
+	}
+
+	return Quiet{loc: loc, start: from, end: to}, nil
+}
+
+// Contains сообщает, приходится ли now на тихие часы
+func (q Quiet) Contains(now time.Time) bool {
+	if q.loc == nil {
+		return false
+	}
+
+	local := now.In(q.loc)
+	m := local.Hour()*60 + local.Minute()
+	if q.start < q.end {
+		return m >= q.start && m < q.end
+	}
+	// через полночь: 22:00–08:00
+	return m >= q.start || m < q.end
+}
+
+func parseClock(v string) (int, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("time %q must be HH:MM", v)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
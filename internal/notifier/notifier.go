@@ -0,0 +1,106 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"pr-service/internal/storage"
+)
+
+const (
+	KindLog     = "log"
+	KindWebhook = "webhook"
+)
+
+var ErrUnknownNotifier = errors.New("unknown notifier")
+
+// Notifier доставляет напоминания ревьюверам.
+// Ошибка — напоминание не доставлено; его повторят в следующий проход.
+type Notifier interface {
+	Remind(r storage.Reminder) error
+}
+
+// New создаёт notifier по имени; webhookURL и timeout нужны только для webhook
+func New(kind string, log *slog.Logger, webhookURL string, timeout time.Duration) (Notifier, error) {
+	const op = "notifier.New"
+
+	switch kind {
+	case KindLog:
+		return Log{log: log}, nil
+	case KindWebhook:
+		if webhookURL == "" {
+			return nil, fmt.Errorf("%s: webhook url is required", op)
+		}
+		return &Webhook{url: webhookURL, client: &http.Client{Timeout: timeout}}, nil
+	default:
+		return nil, fmt.Errorf("%s: %w: %q", op, ErrUnknownNotifier, kind)
+	}
+}
+
+// Log — напоминания в лог сервиса
+type Log struct {
+	log *slog.Logger
+}
+
+func (n Log) Remind(r storage.Reminder) error {
+	n.log.Info("review reminder",
+		slog.String("user_id", r.UserID),
+		slog.String("pull_request_id", r.PullRequestID),
+		slog.String("author_id", r.AuthorID),
+		slog.Time("assigned_at", r.AssignedAt),
+	)
+	return nil
+}
+
+// Webhook — POST напоминания в JSON на внешний адрес; успех — любой ответ 2xx
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// webhookPayload — тело запроса webhook
+type webhookPayload struct {
+	Event           string     `json:"event"` // всегда review_reminder
+	UserID          string     `json:"user_id"`
+	Username        string     `json:"username"`
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	AssignedAt      time.Time  `json:"assignedAt"`
+	LastRemindedAt  *time.Time `json:"lastRemindedAt,omitempty"`
+}
+
+func (n *Webhook) Remind(r storage.Reminder) error {
+	const op = "notifier.Webhook.Remind"
+
+	body, err := json.Marshal(webhookPayload{
+		Event:           "review_reminder",
+		UserID:          r.UserID,
+		Username:        r.Username,
+		PullRequestID:   r.PullRequestID,
+		PullRequestName: r.PullRequestName,
+		AuthorID:        r.AuthorID,
+		AssignedAt:      r.AssignedAt,
+		LastRemindedAt:  r.LastRemindedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: unexpected status %d", op, resp.StatusCode)
+	}
+
+	return nil
+}
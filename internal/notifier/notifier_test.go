@@ -0,0 +1,118 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-service/internal/storage"
+)
+
+func TestWebhookRemindPayload(t *testing.T) {
+	assignedAt := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	remindedAt := assignedAt.Add(4 * time.Hour)
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("content type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n, err := New(KindWebhook, nil, srv.URL, time.Second)
+	if err != nil {
+		t.Fatalf("new webhook: %v", err)
+	}
+	err = n.Remind(storage.Reminder{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorID:        "u1",
+		UserID:          "u2",
+		Username:        "Bob",
+		AssignedAt:      assignedAt,
+		LastRemindedAt:  &remindedAt,
+	})
+	if err != nil {
+		t.Fatalf("remind: %v", err)
+	}
+
+	want := map[string]any{
+		"event":             "review_reminder",
+		"user_id":           "u2",
+		"username":          "Bob",
+		"pull_request_id":   "pr-1",
+		"pull_request_name": "Add search",
+		"author_id":         "u1",
+		"assignedAt":        "2026-03-02T09:30:00Z",
+		"lastRemindedAt":    "2026-03-02T13:30:00Z",
+	}
+	if len(got) != len(want) {
+		t.Errorf("payload = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("payload[%s] = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestWebhookRemindFirstReminderOmitsLastRemindedAt(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	n, err := New(KindWebhook, nil, srv.URL, time.Second)
+	if err != nil {
+		t.Fatalf("new webhook: %v", err)
+	}
+	if err := n.Remind(storage.Reminder{PullRequestID: "pr-1", UserID: "u2", AssignedAt: time.Now()}); err != nil {
+		t.Fatalf("remind: %v", err)
+	}
+	if _, ok := got["lastRemindedAt"]; ok {
+		t.Errorf("payload = %v, want no lastRemindedAt", got)
+	}
+}
+
+func TestWebhookRemindNon2xx(t *testing.T) {
+	for _, status := range []int{http.StatusMovedPermanently, http.StatusBadRequest, http.StatusInternalServerError} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		n, err := New(KindWebhook, nil, srv.URL, time.Second)
+		if err != nil {
+			t.Fatalf("new webhook: %v", err)
+		}
+		err = n.Remind(storage.Reminder{PullRequestID: "pr-1", UserID: "u2"})
+		srv.Close()
+
+		if err == nil || !strings.Contains(err.Error(), "unexpected status") {
+			t.Errorf("status %d: err = %v, want unexpected status", status, err)
+		}
+	}
+}
+
+func TestNewRejectsUnknownKindAndMissingURL(t *testing.T) {
+	if _, err := New("sms", nil, "", time.Second); !errors.Is(err, ErrUnknownNotifier) {
+		t.Errorf("unknown kind: err = %v, want ErrUnknownNotifier", err)
+	}
+	if _, err := New(KindWebhook, nil, "", time.Second); err == nil {
+		t.Error("webhook without url: want error")
+	}
+}
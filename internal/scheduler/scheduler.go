@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"pr-service/internal/lib/logger/sl"
+)
+
+// Job — фоновая задача; now — время текущего прохода
+type Job func(now time.Time) error
+
+type job struct {
+	name string
+	run  Job
+}
+
+// Scheduler раз в interval по очереди запускает зарегистрированные задачи
+type Scheduler struct {
+	log      *slog.Logger
+	interval time.Duration
+	jobs     []job
+}
+
+func New(log *slog.Logger, interval time.Duration) *Scheduler {
+	return &Scheduler{log: log, interval: interval}
+}
+
+// Add регистрирует задачу; вызывать до Run
+func (s *Scheduler) Add(name string, run Job) {
+	s.jobs = append(s.jobs, job{name: name, run: run})
+}
+
+// Run выполняет задачи сразу и затем каждые interval, пока не отменён ctx.
+// Ошибка задачи логируется и не мешает следующим проходам.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(now time.Time) {
+	for _, j := range s.jobs {
+		if err := j.run(now); err != nil {
+			s.log.Error("scheduled job failed", slog.String("job", j.name), sl.Err(err))
+		}
+	}
+}
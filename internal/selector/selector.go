@@ -0,0 +1,339 @@
+package selector
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+	StrategySticky      = "sticky"
+)
+
+// DefaultStickyHalfLife — за сколько вдвое затухает вклад прошлого ревью в стратегии sticky
+const DefaultStickyHalfLife = 14 * 24 * time.Hour
+
+var ErrUnknownStrategy = errors.New("unknown reviewer selection strategy")
+
+// Candidate — кандидат в ревьюверы
+type Candidate struct {
+	ID             int64         // внутренний id пользователя
+	UserID         string        // внешний user_id
+	OpenReviews    int           // сколько OPEN PR пользователь ревьюит сейчас
+	MaxOpenReviews int           // лимит открытых ревью; 0 — без ограничения
+	Skills         []string      // навыки пользователя (go, sql, frontend, ...)
+	Seniority      string        // junior, middle, senior
+	AvailableIn    time.Duration // через сколько у кандидата начнётся рабочее время; 0 — уже работает
+	AuthorHistory  []time.Time   // когда смёржены PR автора, которые кандидат ревьюил (для sticky)
+}
+
+// AtCapacity — кандидат уже ревьюит максимально допустимое число PR
+func (c Candidate) AtCapacity() bool {
+	return c.MaxOpenReviews > 0 && c.OpenReviews >= c.MaxOpenReviews
+}
+
+// Selector выбирает до n ревьюверов из списка кандидатов.
+// rnd — источник случайности этого выбора (см. Source), key — имя команды,
+// в рамках которой идёт выбор (нужен стратегиям с состоянием).
+type Selector interface {
+	Select(rnd *rand.Rand, key string, candidates []Candidate, n int) []Candidate
+}
+
+// New создаёт стратегию по имени
+func New(strategy string) (Selector, error) {
+	switch strategy {
+	case StrategyRandom:
+		return Random{}, nil
+	case StrategyRoundRobin:
+		return NewRoundRobin(), nil
+	case StrategyLeastLoaded:
+		return LeastLoaded{}, nil
+	case StrategySticky:
+		return Sticky{HalfLife: DefaultStickyHalfLife}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, strategy)
+	}
+}
+
+// Random — перемешать и взять первых n
+type Random struct{}
+
+func (Random) Select(rnd *rand.Rand, _ string, candidates []Candidate, n int) []Candidate {
+	out := clone(candidates)
+	rnd.Shuffle(len(out), func(i, j int) {
+		out[i], out[j] = out[j], out[i]
+	})
+	return head(out, n)
+}
+
+// RoundRobin идёт по кандидатам команды по кругу (в порядке user_id),
+// продолжая с того места, где остановился в прошлый раз.
+type RoundRobin struct {
+	mu   sync.Mutex
+	last map[string]string // команда -> user_id последнего выбранного
+}
+
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{last: make(map[string]string)}
+}
+
+func (s *RoundRobin) snapshot() Selector {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := NewRoundRobin()
+	for k, v := range s.last {
+		out.last[k] = v
+	}
+	return out
+}
+
+func (s *RoundRobin) Select(_ *rand.Rand, key string, candidates []Candidate, n int) []Candidate {
+	if len(candidates) == 0 || n <= 0 {
+		return nil
+	}
+
+	out := clone(candidates)
+	sort.Slice(out, func(i, j int) bool { return out[i].UserID < out[j].UserID })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// первый кандидат после последнего выбранного
+	start := sort.Search(len(out), func(i int) bool { return out[i].UserID > s.last[key] })
+	out = append(out[start:], out[:start]...)
+
+	out = head(out, n)
+	s.last[key] = out[len(out)-1].UserID
+
+	return out
+}
+
+// LeastLoaded выбирает кандидатов с наименьшим числом открытых ревью,
+// при равной нагрузке — случайно
+type LeastLoaded struct{}
+
+func (LeastLoaded) Select(rnd *rand.Rand, _ string, candidates []Candidate, n int) []Candidate {
+	out := clone(candidates)
+	rnd.Shuffle(len(out), func(i, j int) {
+		out[i], out[j] = out[j], out[i]
+	})
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].OpenReviews < out[j].OpenReviews
+	})
+	return head(out, n)
+}
+
+// Detached возвращает стратегию, выбор которой не меняет состояние s:
+// для пробного выбора, который не должен сдвигать очередь round_robin
+func Detached(s Selector) Selector {
+	if st, ok := s.(interface{ snapshot() Selector }); ok {
+		return st.snapshot()
+	}
+	return s
+}
+
+// Sticky предпочитает тех, кто недавно ревьюил смёрженные PR того же автора: так ревью идут быстрее.
+// Вклад каждого такого PR затухает вдвое за HalfLife, чтобы пары не закреплялись навсегда.
+// При равной привязанности — наименее загруженные, затем случайно.
+type Sticky struct {
+	HalfLife time.Duration
+}
+
+func (s Sticky) Select(rnd *rand.Rand, _ string, candidates []Candidate, n int) []Candidate {
+	ref := latestMerge(candidates)
+
+	out := clone(candidates)
+	rnd.Shuffle(len(out), func(i, j int) {
+		out[i], out[j] = out[j], out[i]
+	})
+
+	affinity := make(map[int64]float64, len(out))
+	for _, c := range out {
+		affinity[c.ID] = s.Affinity(c, ref)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if ai, aj := affinity[out[i].ID], affinity[out[j].ID]; ai != aj {
+			return ai > aj
+		}
+		return out[i].OpenReviews < out[j].OpenReviews
+	})
+
+	return head(out, n)
+}
+
+// Affinity — привязанность кандидата к автору на момент ref: сумма затухающих вкладов прошлых ревью
+func (s Sticky) Affinity(c Candidate, ref time.Time) float64 {
+	var sum float64
+	for _, mergedAt := range c.AuthorHistory {
+		age := max(ref.Sub(mergedAt), 0)
+		sum += math.Exp2(-float64(age) / float64(s.HalfLife))
+	}
+	return sum
+}
+
+// latestMerge — момент, от которого sticky считает возраст ревью: последний merge среди истории кандидатов.
+// Затухание от него до текущего времени у всех кандидатов общее и порядка не меняет,
+// зато выбор зависит только от кандидатов и сида и повторяется при разборе в любой момент.
+func latestMerge(candidates []Candidate) time.Time {
+	var ref time.Time
+	for _, c := range candidates {
+		for _, mergedAt := range c.AuthorHistory {
+			if mergedAt.After(ref) {
+				ref = mergedAt
+			}
+		}
+	}
+	return ref
+}
+
+// SelectTiered выбирает до n кандидатов, исчерпывая группы по порядку:
+// сначала из первой (самые предпочтительные), затем добирает из следующих.
+func SelectTiered(s Selector, rnd *rand.Rand, key string, tiers [][]Candidate, n int) []Candidate {
+	var out []Candidate
+	for _, tier := range tiers {
+		if len(out) >= n {
+			break
+		}
+		out = append(out, s.Select(rnd, key, tier, n-len(out))...)
+	}
+	return out
+}
+
+// Source выдаёт сиды для отдельных выборов ревьюверов.
+// Сам засевается один раз (из конфига), поэтому последовательность выборов воспроизводима,
+// а сид конкретного выбора сохраняется вместе с назначением и позволяет повторить его при разборе.
+type Source struct {
+	mu   sync.Mutex
+	seed int64
+	pcg  *rand.PCG
+	rnd  *rand.Rand
+}
+
+// NewSource: seed = 0 — случайный сид
+func NewSource(seed int64) *Source {
+	if seed == 0 {
+		seed = rand.Int64N(math.MaxInt64) + 1
+	}
+	pcg := rand.NewPCG(uint64(seed), 0)
+	return &Source{seed: seed, pcg: pcg, rnd: rand.New(pcg)}
+}
+
+// Seed — сид, которым засеян источник
+func (s *Source) Seed() int64 {
+	return s.seed
+}
+
+// Draw возвращает сид для очередного выбора
+func (s *Source) Draw() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Int64()
+}
+
+// Peek возвращает сид, который выдаст следующий Draw, не сдвигая источник
+func (s *Source) Peek() int64 {
+	return s.Clone().Draw()
+}
+
+// Clone — независимая копия источника в текущем состоянии: выдаст те же сиды,
+// что и s, но её Draw не сдвигает s (для пробных прогонов)
+func (s *Source) Clone() *Source {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pcg := *s.pcg
+	return &Source{seed: s.seed, pcg: &pcg, rnd: rand.New(&pcg)}
+}
+
+// NewRand — генератор для выбора с данным сидом; одинаковый сид даёт одинаковую последовательность
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), 0))
+}
+
+// Registry хранит по одному экземпляру каждой стратегии
+// и знает, какая стратегия назначена какой команде.
+type Registry struct {
+	def       string
+	teams     map[string]string
+	selectors map[string]Selector
+}
+
+// NewRegistry: def — стратегия по умолчанию, teams — переопределения для команд,
+// stickyHalfLife — период затухания для sticky (0 — DefaultStickyHalfLife)
+func NewRegistry(def string, teams map[string]string, stickyHalfLife time.Duration) (*Registry, error) {
+	const op = "selector.NewRegistry"
+
+	r := &Registry{
+		def:       def,
+		teams:     teams,
+		selectors: make(map[string]Selector),
+	}
+
+	for _, name := range []string{StrategyRandom, StrategyRoundRobin, StrategyLeastLoaded, StrategySticky} {
+		s, err := New(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		r.selectors[name] = s
+	}
+	if stickyHalfLife > 0 {
+		r.selectors[StrategySticky] = Sticky{HalfLife: stickyHalfLife}
+	}
+
+	if _, ok := r.selectors[def]; !ok {
+		return nil, fmt.Errorf("%s: %w: %q", op, ErrUnknownStrategy, def)
+	}
+	for team, name := range teams {
+		if _, ok := r.selectors[name]; !ok {
+			return nil, fmt.Errorf("%s: team %s: %w: %q", op, team, ErrUnknownStrategy, name)
+		}
+	}
+
+	return r, nil
+}
+
+// Has сообщает, известна ли стратегия с таким именем
+func (r *Registry) Has(name string) bool {
+	_, ok := r.selectors[name]
+	return ok
+}
+
+// NameForTeam возвращает имя стратегии команды.
+// override — стратегия из настроек команды в БД, имеет приоритет над конфигом.
+func (r *Registry) NameForTeam(team, override string) string {
+	if r.Has(override) {
+		return override
+	}
+	if name, ok := r.teams[team]; ok {
+		return name
+	}
+	return r.def
+}
+
+// ForTeam возвращает стратегию команды (или стратегию по умолчанию)
+func (r *Registry) ForTeam(team, override string) Selector {
+	return r.selectors[r.NameForTeam(team, override)]
+}
+
+func clone(candidates []Candidate) []Candidate {
+	out := make([]Candidate, len(candidates))
+	copy(out, candidates)
+	return out
+}
+
+func head(candidates []Candidate, n int) []Candidate {
+	if n < 0 {
+		n = 0
+	}
+	if len(candidates) > n {
+		return candidates[:n]
+	}
+	return candidates
+}
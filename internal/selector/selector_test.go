@@ -0,0 +1,104 @@
+package selector
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// userIDs — user_id выбранных кандидатов по порядку
+func userIDs(candidates []Candidate) []string {
+	out := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		out = append(out, c.UserID)
+	}
+	return out
+}
+
+func TestRoundRobinContinuesCycle(t *testing.T) {
+	candidates := []Candidate{{UserID: "c"}, {UserID: "a"}, {UserID: "b"}}
+	s := NewRoundRobin()
+
+	tests := []struct {
+		key  string
+		n    int
+		want []string
+	}{
+		{key: "backend", n: 1, want: []string{"a"}},
+		{key: "backend", n: 2, want: []string{"b", "c"}},
+		{key: "backend", n: 2, want: []string{"a", "b"}},
+		{key: "frontend", n: 1, want: []string{"a"}}, // у каждой команды свой круг
+	}
+	for i, tt := range tests {
+		if got := userIDs(s.Select(nil, tt.key, candidates, tt.n)); !slices.Equal(got, tt.want) {
+			t.Errorf("call %d (%s): got %v, want %v", i, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestLeastLoadedBreaksTiesRandomly(t *testing.T) {
+	candidates := []Candidate{{UserID: "busy", OpenReviews: 2}, {UserID: "a"}, {UserID: "b"}}
+
+	picked := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		got := LeastLoaded{}.Select(NewRand(int64(i)), "backend", candidates, 2)
+		if len(got) != 2 || got[0].OpenReviews != 0 || got[1].OpenReviews != 0 {
+			t.Fatalf("got %v, want the two least loaded", userIDs(got))
+		}
+		picked[got[0].UserID]++
+	}
+	if picked["a"] == 0 || picked["b"] == 0 {
+		t.Errorf("first picks = %v, want both tied candidates", picked)
+	}
+}
+
+func TestRegistryForTeam(t *testing.T) {
+	r, err := NewRegistry(StrategyRandom, map[string]string{"backend": StrategyRoundRobin}, 0)
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	if _, ok := r.ForTeam("backend", "").(*RoundRobin); !ok {
+		t.Errorf("backend selector = %T, want *RoundRobin", r.ForTeam("backend", ""))
+	}
+	if _, ok := r.ForTeam("frontend", "").(Random); !ok {
+		t.Errorf("frontend selector = %T, want Random", r.ForTeam("frontend", ""))
+	}
+	// настройка команды в БД важнее конфига
+	if _, ok := r.ForTeam("backend", StrategyLeastLoaded).(LeastLoaded); !ok {
+		t.Errorf("backend selector with override = %T, want LeastLoaded", r.ForTeam("backend", StrategyLeastLoaded))
+	}
+
+	if _, err := NewRegistry("fastest", nil, 0); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("unknown default: err = %v, want ErrUnknownStrategy", err)
+	}
+	if _, err := NewRegistry(StrategyRandom, map[string]string{"backend": "fastest"}, 0); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("unknown team strategy: err = %v, want ErrUnknownStrategy", err)
+	}
+}
+
+func TestStickyDependsOnlyOnCandidates(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2020, month, d, 12, 0, 0, 0, time.UTC) }
+	candidates := []Candidate{
+		{ID: 1, UserID: "old", OpenReviews: 0, AuthorHistory: []time.Time{day(1, 1), day(1, 2)}},
+		{ID: 2, UserID: "recent", OpenReviews: 3, AuthorHistory: []time.Time{day(3, 1)}},
+		{ID: 3, UserID: "stranger", OpenReviews: 0},
+	}
+	s := Sticky{HalfLife: DefaultStickyHalfLife}
+
+	// история давно в прошлом, но порядок задаёт возраст ревью относительно друг друга
+	want := []string{"recent", "old", "stranger"}
+	for seed := int64(1); seed <= 5; seed++ {
+		got := userIDs(s.Select(NewRand(seed), "team", candidates, 3))
+		if !slices.Equal(got, want) {
+			t.Fatalf("seed %d: got %v, want %v", seed, got, want)
+		}
+	}
+
+	// без истории sticky работает как least_loaded: повтор по сиду даёт тот же выбор
+	fresh := []Candidate{{ID: 1, UserID: "a"}, {ID: 2, UserID: "b"}, {ID: 3, UserID: "c"}}
+	first := userIDs(s.Select(NewRand(42), "team", fresh, 1))
+	if again := userIDs(s.Select(NewRand(42), "team", fresh, 1)); !slices.Equal(first, again) {
+		t.Errorf("replay with the same seed: got %v, want %v", again, first)
+	}
+}
@@ -0,0 +1,212 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"pr-service/internal/storage"
+	"time"
+)
+
+// SetUserAbsence планирует отсутствие пользователя, заменяя прежнее.
+// Если период уже начался, ревью этого пользователя передаются сразу, не дожидаясь планировщика;
+// отсутствия остальных обрабатывает только планировщик.
+func (s *Storage) SetUserAbsence(userID string, start, end time.Time) (storage.User, error) {
+	const op = "storage.sqlite.SetUserAbsence"
+
+	now := time.Now()
+	if !start.Before(end) {
+		return storage.User{}, fmt.Errorf("%w: end must be after start", storage.ErrInvalidAbsence)
+	}
+	if !end.After(now) {
+		return storage.User{}, fmt.Errorf("%w: absence has already ended", storage.ErrInvalidAbsence)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.User{}, fmt.Errorf("%s: begin tx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var intID, teamID int64
+	var teamName string
+	err = tx.QueryRow(`
+        SELECT u.id, u.team_id, t.name
+        FROM users u
+        JOIN teams t ON u.team_id = t.id
+        WHERE u.user_id = ?`, userID,
+	).Scan(&intID, &teamID, &teamName)
+	if err == sql.ErrNoRows {
+		return storage.User{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	started := !start.After(now)
+	if _, err := tx.Exec(`
+        INSERT INTO user_absences(user_id, starts_at, ends_at, handed_over)
+        VALUES(?, ?, ?, ?)
+        ON CONFLICT(user_id) DO UPDATE
+        SET starts_at = excluded.starts_at, ends_at = excluded.ends_at, handed_over = excluded.handed_over`,
+		intID, sqlTime(start), sqlTime(end), boolToInt(started)); err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if started {
+		if _, err := s.handOverReviews(tx, teamID, teamName, []int64{intID}, storage.EventReasonAbsent); err != nil {
+			return storage.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.User{}, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return s.getUser(userID)
+}
+
+// ProcessAbsences — проход планировщика: у начавшихся отсутствий передаёт открытые ревью
+// другим участникам команды (как BulkDeactivateUsersAndReassign), закончившиеся удаляет,
+// после чего вернувшиеся могут забрать ожидающие места ревьюверов.
+func (s *Storage) ProcessAbsences(now time.Time) (storage.AbsenceRunResult, error) {
+	const op = "storage.sqlite.ProcessAbsences"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.AbsenceRunResult{}, fmt.Errorf("%s: begin tx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res := storage.AbsenceRunResult{StartedUserIDs: []string{}}
+
+	// закончившиеся
+	res.EndedUserIDs, err = absenceUserIDs(tx, `
+        SELECT u.user_id
+        FROM user_absences a
+        JOIN users u ON a.user_id = u.id
+        WHERE a.ends_at <= ?
+        ORDER BY u.id`, sqlTime(now))
+	if err != nil {
+		return storage.AbsenceRunResult{}, fmt.Errorf("%s: query ended: %w", op, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM user_absences WHERE ends_at <= ?`, sqlTime(now)); err != nil {
+		return storage.AbsenceRunResult{}, fmt.Errorf("%s: delete ended: %w", op, err)
+	}
+
+	// начавшиеся, но ещё не обработанные
+	rows, err := tx.Query(`
+        SELECT u.id, u.user_id, u.team_id, t.name
+        FROM user_absences a
+        JOIN users u ON a.user_id = u.id
+        JOIN teams t ON u.team_id = t.id
+        WHERE a.handed_over = 0 AND a.starts_at <= ? AND a.ends_at > ?
+        ORDER BY u.id`, sqlTime(now), sqlTime(now))
+	if err != nil {
+		return storage.AbsenceRunResult{}, fmt.Errorf("%s: query started: %w", op, err)
+	}
+	defer rows.Close()
+
+	// ревью передаются внутри команды пользователя, поэтому группируем по командам
+	type team struct {
+		id    int64
+		name  string
+		users []int64
+	}
+	var teams []*team
+	byID := make(map[int64]*team)
+	for rows.Next() {
+		var intID, teamID int64
+		var extID, teamName string
+		if err := rows.Scan(&intID, &extID, &teamID, &teamName); err != nil {
+			return storage.AbsenceRunResult{}, fmt.Errorf("%s: scan started: %w", op, err)
+		}
+		t, ok := byID[teamID]
+		if !ok {
+			t = &team{id: teamID, name: teamName}
+			byID[teamID] = t
+			teams = append(teams, t)
+		}
+		t.users = append(t.users, intID)
+		res.StartedUserIDs = append(res.StartedUserIDs, extID)
+	}
+	if err := rows.Err(); err != nil {
+		return storage.AbsenceRunResult{}, fmt.Errorf("%s: started rows err: %w", op, err)
+	}
+	rows.Close()
+
+	for _, t := range teams {
+		handed, err := s.handOverReviews(tx, t.id, t.name, t.users, storage.EventReasonAbsent)
+		if err != nil {
+			return storage.AbsenceRunResult{}, fmt.Errorf("%s: team %s: %w", op, t.name, err)
+		}
+		res.ReassignedCount += handed.reassigned
+		res.RemovedAssignments += handed.removed
+		res.SeniorUnavailable = append(res.SeniorUnavailable, handed.seniorUnavailable...)
+
+		for _, id := range t.users {
+			if _, err := tx.Exec(`UPDATE user_absences SET handed_over = 1 WHERE user_id = ?`, id); err != nil {
+				return storage.AbsenceRunResult{}, fmt.Errorf("%s: mark handed over: %w", op, err)
+			}
+		}
+	}
+
+	// вернувшиеся могут забрать ожидающие места ревьюверов
+	if len(res.EndedUserIDs) > 0 {
+		if err := s.fillPendingReviewers(tx); err != nil {
+			return storage.AbsenceRunResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.AbsenceRunResult{}, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// userAbsence — текущее или будущее отсутствие пользователя; nil, если его нет
+func userAbsence(q querier, userID string) (*storage.Absence, error) {
+	var a storage.Absence
+	var handedOver int
+	err := q.QueryRow(`
+        SELECT a.starts_at, a.ends_at, a.handed_over
+        FROM user_absences a
+        JOIN users u ON a.user_id = u.id
+        WHERE u.user_id = ? AND a.ends_at > ?`, userID, sqlTime(time.Now()),
+	).Scan(&a.Start, &a.End, &handedOver)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.HandedOver = handedOver == 1
+
+	return &a, nil
+}
+
+// absenceUserIDs выполняет запрос, возвращающий внешние user_id
+func absenceUserIDs(q querier, query string, args ...any) ([]string, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// sqlTime — время в формате CURRENT_TIMESTAMP (UTC, до секунды):
+// в таком виде строки в SQLite сравниваются так же, как моменты времени
+func sqlTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"pr-service/internal/selector"
+	"pr-service/internal/storage"
+)
+
+func TestSetUserAbsenceHandsOverOnlyThisUser(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "early", "late", "spare")
+	// по кругу: первый PR достаётся early, второй — late
+	strategy := selector.StrategyRoundRobin
+	if _, err := s.UpdateTeamSettings("backend", storage.TeamSettingsUpdate{Strategy: &strategy}); err != nil {
+		t.Fatalf("team settings: %v", err)
+	}
+	mustCreatePR(t, s, "pr-early", "author")
+	mustCreatePR(t, s, "pr-late", "author")
+
+	// отсутствие early уже началось, но планировщик до него ещё не дошёл
+	now := time.Now()
+	if _, err := s.SetUserAbsence("early", now.Add(time.Hour), now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("set absence early: %v", err)
+	}
+	if _, err := s.db.Exec(`UPDATE user_absences SET starts_at = ?`, sqlTime(now.Add(-time.Minute))); err != nil {
+		t.Fatalf("move absence start: %v", err)
+	}
+
+	if _, err := s.SetUserAbsence("late", now.Add(-time.Minute), now.Add(time.Hour)); err != nil {
+		t.Fatalf("set absence late: %v", err)
+	}
+
+	if got := mustGetPR(t, s, "pr-late").AssignedReviewers; slices.Contains(got, "late") {
+		t.Errorf("pr-late reviewers = %v, want late handed over", got)
+	}
+	if got := mustGetPR(t, s, "pr-early").AssignedReviewers; !slices.Equal(got, []string{"early"}) {
+		t.Errorf("pr-early reviewers = %v, want early left to the scheduler", got)
+	}
+
+	res, err := s.ProcessAbsences(time.Now())
+	if err != nil {
+		t.Fatalf("process absences: %v", err)
+	}
+	if !slices.Equal(res.StartedUserIDs, []string{"early"}) {
+		t.Errorf("started = %v, want [early]", res.StartedUserIDs)
+	}
+	if got := mustGetPR(t, s, "pr-early").AssignedReviewers; slices.Contains(got, "early") {
+		t.Errorf("pr-early reviewers = %v, want early handed over", got)
+	}
+}
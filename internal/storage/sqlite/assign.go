@@ -0,0 +1,543 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"pr-service/internal/selector"
+	"pr-service/internal/storage"
+	"slices"
+	"time"
+)
+
+// assignment — выбранный ревьювер и откуда он взялся
+type assignment struct {
+	candidate selector.Candidate
+	source    string   // storage.ReviewerSource*
+	teamID    int64    // команда ревьювера
+	matched   []string // метки PR, закрытые навыками ревьювера
+	strategy  string   // стратегия, которой выбран; пусто — указан явно
+	seed      int64    // сид выбора
+}
+
+// assignmentRequest — всё, что нужно для подбора ревьюверов нового PR
+type assignmentRequest struct {
+	authorID     int64
+	teamID       int64
+	teamName     string
+	changedFiles []string
+	labels       []string           // уже нормализованные метки PR
+	excluded     map[int64]struct{} // кого нельзя назначать (исключения PR и пары с автором)
+	requested    []int64            // явно указанные ревьюверы, уже проверенные; занимают места первыми
+	preview      bool               // пробный выбор: не сдвигает источник сидов и очередь round_robin
+}
+
+// newAssignmentRequest находит автора нового PR и тех, кого нельзя назначать:
+// исключённых для этого PR (они же возвращаются отдельно) и пары-исключения автора.
+// Явно указанные ревьюверы проверяются все сразу; неподходящие — *storage.InvalidReviewersError.
+func newAssignmentRequest(q querier, newPR storage.NewPullRequest) (assignmentRequest, []int64, error) {
+	req := assignmentRequest{
+		changedFiles: newPR.ChangedFiles,
+		labels:       normalizeTags(newPR.Labels),
+	}
+
+	err := q.QueryRow(`
+        SELECT u.id, u.team_id, t.name
+        FROM users u
+        JOIN teams t ON u.team_id = t.id
+        WHERE u.user_id = ?`, newPR.AuthorID,
+	).Scan(&req.authorID, &req.teamID, &req.teamName)
+	if err == sql.ErrNoRows {
+		return assignmentRequest{}, nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return assignmentRequest{}, nil, err
+	}
+
+	prExcluded, err := userIntIDs(q, newPR.ExcludedReviewers)
+	if err != nil {
+		return assignmentRequest{}, nil, err
+	}
+	req.excluded, err = exclusionPartners(q, req.authorID)
+	if err != nil {
+		return assignmentRequest{}, nil, err
+	}
+	for _, id := range prExcluded {
+		req.excluded[id] = struct{}{}
+	}
+
+	req.requested, err = requestedReviewers(q, req, prExcluded, newPR.RequestedReviewers)
+	if err != nil {
+		return assignmentRequest{}, nil, err
+	}
+
+	return req, prExcluded, nil
+}
+
+// requestedReviewers проверяет явно указанных ревьюверов: они должны быть активными,
+// сейчас не отсутствующими участниками команды автора, не автором и не исключёнными для PR.
+// Лимит открытых ревью не проверяется — явный выбор автора важнее.
+func requestedReviewers(q querier, req assignmentRequest, prExcluded []int64, userIDs []string) ([]int64, error) {
+	var ids []int64
+	var invalid []storage.InvalidReviewer
+	seen := make(map[string]struct{}, len(userIDs))
+
+	for _, uid := range userIDs {
+		if _, ok := seen[uid]; ok {
+			continue
+		}
+		seen[uid] = struct{}{}
+
+		var id, teamID int64
+		var activeInt, absent int
+		err := q.QueryRow(`
+            SELECT u.id, u.team_id, u.is_active,
+                   EXISTS (SELECT 1 FROM user_absences a
+                           WHERE a.user_id = u.id AND a.starts_at <= ? AND a.ends_at > ?)
+            FROM users u
+            WHERE u.user_id = ?`, sqlTime(time.Now()), sqlTime(time.Now()), uid,
+		).Scan(&id, &teamID, &activeInt, &absent)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		reason := ""
+		switch {
+		case err == sql.ErrNoRows:
+			reason = storage.ExclusionReasonNotFound
+		case id == req.authorID:
+			reason = storage.ExclusionReasonAuthor
+		case teamID != req.teamID:
+			reason = storage.ExclusionReasonNotTeamMember
+		case slices.Contains(prExcluded, id):
+			reason = storage.ExclusionReasonExcluded
+		case hasID(req.excluded, id):
+			reason = storage.ExclusionReasonPair
+		case activeInt != 1:
+			reason = storage.ExclusionReasonInactive
+		case absent == 1:
+			reason = storage.ExclusionReasonAbsent
+		}
+		if reason != "" {
+			invalid = append(invalid, storage.InvalidReviewer{UserID: uid, Reason: reason})
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	if len(invalid) > 0 {
+		return nil, &storage.InvalidReviewersError{Reviewers: invalid}
+	}
+	return ids, nil
+}
+
+// assignmentPlan — кого назначить, сколько мест оставить в очереди и какие политики не выполнены
+type assignmentPlan struct {
+	picked   []assignment
+	pending  int
+	warnings []string // storage.Warning*
+	strategy string
+	seed     int64
+}
+
+// planAssignment подбирает ревьюверов нового PR, ничего не записывая:
+// сначала владельцы изменённых путей, затем остальные из команды автора,
+// затем (если разрешено) запасные команды по порядку.
+// Внутри каждой группы предпочитаются те, чьи навыки закрывают больше меток PR,
+// а при равенстве — те, у кого сейчас рабочее время (или наступит раньше).
+// Если команда требует senior, одно место сначала отдаётся senior'у.
+func (s *Storage) planAssignment(q querier, req assignmentRequest) (assignmentPlan, error) {
+	settings, err := teamSettings(q, req.teamID)
+	if err != nil {
+		return assignmentPlan{}, fmt.Errorf("team settings: %w", err)
+	}
+	sel := s.selectors.ForTeam(req.teamName, settings.Strategy)
+	strategy := s.selectors.NameForTeam(req.teamName, settings.Strategy)
+	draw := s.draw
+	if req.preview {
+		sel = selector.Detached(sel)
+		draw = s.peek
+	}
+	seed, rnd := draw()
+	need := settings.ReviewerCount
+
+	// кандидаты: активные из команды автора, не он сам
+	candidates, err := activeCandidates(q, req.teamID)
+	if err != nil {
+		return assignmentPlan{}, fmt.Errorf("query candidates: %w", err)
+	}
+	candidates, err = withAuthorHistory(q, candidates, req.authorID)
+	if err != nil {
+		return assignmentPlan{}, fmt.Errorf("query author history: %w", err)
+	}
+
+	// владельцы изменённых путей идут первыми
+	rules, err := teamCodeOwners(q, req.teamID)
+	if err != nil {
+		return assignmentPlan{}, fmt.Errorf("codeowners: %w", err)
+	}
+	owners := rules.OwnersOf(req.changedFiles)
+	isOwner := func(c selector.Candidate) bool { return slices.Contains(owners, c.UserID) }
+
+	ownRank := func(c selector.Candidate) []int {
+		ownerRank := 1
+		if isOwner(c) {
+			ownerRank = 0
+		}
+		return []int{ownerRank, -len(matchedLabels(c.Skills, req.labels)), availabilityRank(c)}
+	}
+	labelRank := func(c selector.Candidate) []int {
+		return []int{-len(matchedLabels(c.Skills, req.labels)), availabilityRank(c)}
+	}
+
+	plan := assignmentPlan{strategy: strategy, seed: seed}
+	taken := map[int64]struct{}{req.authorID: {}}
+	for id := range req.excluded {
+		taken[id] = struct{}{}
+	}
+
+	// pick выбирает до n ещё не взятых кандидатов команды и добавляет их в план
+	pick := func(pool []selector.Candidate, n int, teamName string, teamID int64, rank func(selector.Candidate) []int, source func(selector.Candidate) string) int {
+		if n <= 0 {
+			return 0
+		}
+		tiers := rankTiers(without(pool, taken), rank)
+		picked := selector.SelectTiered(sel, rnd, teamName, tiers, n)
+		for _, c := range picked {
+			plan.picked = append(plan.picked, assignment{
+				candidate: c,
+				source:    source(c),
+				teamID:    teamID,
+				matched:   matchedLabels(c.Skills, req.labels),
+				strategy:  strategy,
+				seed:      seed,
+			})
+			taken[c.ID] = struct{}{}
+		}
+		return len(picked)
+	}
+	ownSource := func(c selector.Candidate) string {
+		if isOwner(c) {
+			return storage.ReviewerSourceCodeOwner
+		}
+		return storage.ReviewerSourceTeam
+	}
+	fallbackSource := func(selector.Candidate) string { return storage.ReviewerSourceFallback }
+
+	// явно указанные занимают места первыми, даже сверх reviewer_count
+	seniorMissing := settings.RequireSenior
+	for _, id := range req.requested {
+		i := slices.IndexFunc(candidates, func(c selector.Candidate) bool { return c.ID == id })
+		if i < 0 {
+			continue // проверены в newAssignmentRequest; сюда не попадаем
+		}
+		c := candidates[i]
+		plan.picked = append(plan.picked, assignment{
+			candidate: c,
+			source:    storage.ReviewerSourceRequested,
+			teamID:    req.teamID,
+			matched:   matchedLabels(c.Skills, req.labels),
+		})
+		taken[c.ID] = struct{}{}
+		if c.Seniority == storage.SenioritySenior {
+			seniorMissing = false
+		}
+	}
+
+	// выбираем среди тех, у кого не исчерпан лимит
+	free, full := splitByCapacity(candidates)
+	fullCount := len(without(full, taken))
+
+	if seniorMissing && len(plan.picked) < need && pick(seniors(free), 1, req.teamName, req.teamID, ownRank, ownSource) > 0 {
+		seniorMissing = false
+	}
+
+	// своего senior нет — одно место придерживаем для senior'а из запасных команд
+	reserved := 0
+	if seniorMissing && len(plan.picked) < need && settings.AllowCrossTeamFallback && len(settings.FallbackTeams) > 0 {
+		reserved = 1
+	}
+
+	pick(free, need-reserved-len(plan.picked), req.teamName, req.teamID, ownRank, ownSource)
+
+	// своих не хватило — добираем из запасных команд
+	if len(plan.picked) < need && settings.AllowCrossTeamFallback {
+		for _, fallback := range settings.FallbackTeams {
+			if len(plan.picked) >= need {
+				break
+			}
+
+			var fbTeamID int64
+			if err := q.QueryRow(`SELECT id FROM teams WHERE name = ?`, fallback).Scan(&fbTeamID); err != nil {
+				continue // команду удалили или переименовали — пропускаем
+			}
+
+			fbCandidates, err := activeCandidates(q, fbTeamID)
+			if err != nil {
+				return assignmentPlan{}, fmt.Errorf("query fallback candidates: %w", err)
+			}
+			fbCandidates, err = withAuthorHistory(q, fbCandidates, req.authorID)
+			if err != nil {
+				return assignmentPlan{}, fmt.Errorf("query author history: %w", err)
+			}
+
+			fbFree, fbFull := splitByCapacity(fbCandidates)
+			fullCount += len(without(fbFull, taken))
+
+			if seniorMissing && pick(seniors(fbFree), 1, fallback, fbTeamID, labelRank, fallbackSource) > 0 {
+				seniorMissing = false
+				reserved = 0
+			}
+			pick(fbFree, need-reserved-len(plan.picked), fallback, fbTeamID, labelRank, fallbackSource)
+		}
+	}
+
+	// senior так и не нашёлся — отложенное место достаётся своей команде
+	if reserved > 0 {
+		pick(free, need-len(plan.picked), req.teamName, req.teamID, ownRank, ownSource)
+	}
+
+	if seniorMissing {
+		plan.warnings = append(plan.warnings, storage.WarningSeniorUnavailable)
+	}
+
+	// места, которые не заняли только из-за лимитов, ждут в очереди
+	plan.pending = max(0, min(need-len(plan.picked), fullCount))
+
+	return plan, nil
+}
+
+// insertAssignment записывает назначение ревьювера на PR и событие reviewer_assigned
+func insertAssignment(q querier, prIntID int64, a assignment) error {
+	matchedJSON, err := json.Marshal(nonNil(a.matched))
+	if err != nil {
+		return err
+	}
+
+	// ревьювер выбран не стратегией (указан явно) — сида нет
+	var seed any = a.seed
+	if a.strategy == "" {
+		seed = nil
+	}
+
+	_, err = q.Exec(`
+        INSERT INTO pr_reviewers(pr_id, reviewer_id, source, source_team_id, matched_labels, strategy, selection_seed, assigned_at)
+        VALUES(?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`,
+		prIntID, a.candidate.ID, a.source, a.teamID, string(matchedJSON), a.strategy, seed)
+	if err != nil {
+		return err
+	}
+
+	return recordEvent(q, prIntID, prEvent{
+		typ:    storage.EventReviewerAssigned,
+		userID: a.candidate.ID,
+		reason: a.source,
+		actor:  storage.EventActorSystem,
+	})
+}
+
+// updateAssignment заменяет ревьювера oldReviewerID на PR новым назначением; итог ревью прежнего сбрасывается,
+// а SLA и время ревью для нового считаются заново.
+// Замена попадает в историю с причиной reason от имени actor.
+func updateAssignment(q querier, prIntID, oldReviewerID int64, a assignment, reason, actor string) error {
+	matchedJSON, err := json.Marshal(nonNil(a.matched))
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(`
+        UPDATE pr_reviewers
+        SET reviewer_id = ?, source = ?, source_team_id = ?, matched_labels = ?, strategy = ?, selection_seed = ?,
+            verdict = NULL, verdict_at = NULL, assigned_at = CURRENT_TIMESTAMP, first_action_at = NULL, completed_at = NULL
+        WHERE pr_id = ? AND reviewer_id = ?`,
+		a.candidate.ID, a.source, a.teamID, string(matchedJSON), a.strategy, a.seed, prIntID, oldReviewerID)
+	if err != nil {
+		return err
+	}
+
+	return recordEvent(q, prIntID, prEvent{
+		typ:           storage.EventReviewerReplaced,
+		userID:        oldReviewerID,
+		replacementID: a.candidate.ID,
+		reason:        reason,
+		actor:         actor,
+	})
+}
+
+// draw — сид и генератор для очередного выбора ревьюверов
+func (s *Storage) draw() (int64, *rand.Rand) {
+	seed := s.source.Draw()
+	return seed, selector.NewRand(seed)
+}
+
+// peek — как draw, но не сдвигает источник: тот же сид получит следующий настоящий выбор
+func (s *Storage) peek() (int64, *rand.Rand) {
+	seed := s.source.Peek()
+	return seed, selector.NewRand(seed)
+}
+
+// rankTiers группирует кандидатов по рангу; группы идут от меньшего ранга к большему.
+// Ранг сравнивается покомпонентно, так несколько предпочтений складываются по важности.
+func rankTiers(candidates []selector.Candidate, rank func(selector.Candidate) []int) [][]selector.Candidate {
+	type tier struct {
+		rank  []int
+		items []selector.Candidate
+	}
+
+	var tiers []*tier
+	for _, c := range candidates {
+		r := rank(c)
+		idx := slices.IndexFunc(tiers, func(t *tier) bool { return slices.Equal(t.rank, r) })
+		if idx < 0 {
+			tiers = append(tiers, &tier{rank: r})
+			idx = len(tiers) - 1
+		}
+		tiers[idx].items = append(tiers[idx].items, c)
+	}
+
+	slices.SortFunc(tiers, func(a, b *tier) int { return slices.Compare(a.rank, b.rank) })
+
+	out := make([][]selector.Candidate, 0, len(tiers))
+	for _, t := range tiers {
+		out = append(out, t.items)
+	}
+	return out
+}
+
+// unavailableForPR — кого нельзя поставить на место ревьювера PR:
+// уже назначенные, автор, исключённые для PR и пары-исключения автора
+func unavailableForPR(q querier, prIntID, authorIntID int64) (map[int64]struct{}, error) {
+	unavailable, err := assignedReviewerIDs(q, prIntID)
+	if err != nil {
+		return nil, err
+	}
+	unavailable[authorIntID] = struct{}{}
+
+	excluded, err := excludedForPR(q, prIntID, authorIntID)
+	if err != nil {
+		return nil, err
+	}
+	for id := range excluded {
+		unavailable[id] = struct{}{}
+	}
+
+	return unavailable, nil
+}
+
+// withAuthorHistory возвращает копию кандидатов с моментами merge PR автора,
+// которые каждый из них ревьюил (по ним стратегия sticky считает привязанность)
+func withAuthorHistory(q querier, candidates []selector.Candidate, authorIntID int64) ([]selector.Candidate, error) {
+	rows, err := q.Query(`
+        SELECT r.reviewer_id, pr.merged_at
+        FROM pr_reviewers r
+        JOIN pull_requests pr ON r.pr_id = pr.id
+        WHERE pr.author_id = ? AND pr.status = 'MERGED' AND pr.merged_at IS NOT NULL
+        ORDER BY pr.merged_at DESC`, authorIntID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make(map[int64][]time.Time)
+	for rows.Next() {
+		var id int64
+		var mergedAt time.Time
+		if err := rows.Scan(&id, &mergedAt); err != nil {
+			return nil, err
+		}
+		history[id] = append(history[id], mergedAt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]selector.Candidate, len(candidates))
+	copy(out, candidates)
+	for i := range out {
+		out[i].AuthorHistory = history[out[i].ID]
+	}
+	return out, nil
+}
+
+// seniorWanted — команда автора требует senior, а среди ревьюверов PR (кроме exceptID) его нет
+func seniorWanted(q querier, authorTeamID, prIntID, exceptID int64) (bool, error) {
+	settings, err := teamSettings(q, authorTeamID)
+	if err != nil {
+		return false, err
+	}
+	if !settings.RequireSenior {
+		return false, nil
+	}
+
+	var count int
+	err = q.QueryRow(`
+        SELECT COUNT(*)
+        FROM pr_reviewers r
+        JOIN users u ON r.reviewer_id = u.id
+        WHERE r.pr_id = ? AND r.reviewer_id != ? AND u.seniority = ?`,
+		prIntID, exceptID, storage.SenioritySenior,
+	).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count == 0, nil
+}
+
+// replacementRank — ранг кандидата при замене или доборе ревьювера:
+// senior вперёд, если его не хватает, затем покрытие меток PR навыками, затем рабочее время
+func replacementRank(labels []string, wantSenior bool) func(selector.Candidate) []int {
+	return func(c selector.Candidate) []int {
+		seniorRank := 0
+		if wantSenior && c.Seniority != storage.SenioritySenior {
+			seniorRank = 1
+		}
+		return []int{seniorRank, -len(matchedLabels(c.Skills, labels)), availabilityRank(c)}
+	}
+}
+
+// availabilityRank — через сколько полных часов кандидат выйдет на работу (0 — уже работает).
+// Округление до часа оставляет стратегии выбор среди примерно одинаково доступных.
+func availabilityRank(c selector.Candidate) int {
+	return int(math.Ceil(c.AvailableIn.Hours()))
+}
+
+// seniors оставляет только кандидатов уровня senior
+func seniors(candidates []selector.Candidate) []selector.Candidate {
+	var out []selector.Candidate
+	for _, c := range candidates {
+		if c.Seniority == storage.SenioritySenior {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// nonNil — пустой срез вместо nil, чтобы в JSON попадал [], а не null
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// matchedLabels — метки PR, которые закрывают навыки кандидата
+func matchedLabels(skills, labels []string) []string {
+	var matched []string
+	for _, l := range labels {
+		if slices.Contains(skills, l) {
+			matched = append(matched, l)
+		}
+	}
+	return matched
+}
+
+// sourceFor — источник замены: своя команда автора или запасная
+func sourceFor(reviewerTeamID, authorTeamID int64) string {
+	if reviewerTeamID == authorTeamID {
+		return storage.ReviewerSourceTeam
+	}
+	return storage.ReviewerSourceFallback
+}
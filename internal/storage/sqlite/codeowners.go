@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"pr-service/internal/lib/codeowners"
+	"pr-service/internal/storage"
+	"time"
+)
+
+// GetTeamCodeOwners возвращает загруженный для команды CODEOWNERS
+func (s *Storage) GetTeamCodeOwners(teamName string) (storage.TeamCodeOwners, error) {
+	const op = "storage.sqlite.GetTeamCodeOwners"
+
+	var (
+		content   string
+		updatedAt time.Time
+	)
+	err := s.db.QueryRow(`
+        SELECT c.content, c.updated_at
+        FROM team_codeowners c
+        JOIN teams t ON c.team_id = t.id
+        WHERE t.name = ?`, teamName,
+	).Scan(&content, &updatedAt)
+	if err == sql.ErrNoRows {
+		return storage.TeamCodeOwners{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return storage.TeamCodeOwners{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rules, err := codeowners.Parse(content)
+	if err != nil {
+		return storage.TeamCodeOwners{}, fmt.Errorf("%s: stored rules: %w", op, err)
+	}
+
+	return storage.TeamCodeOwners{
+		TeamName:   teamName,
+		Content:    content,
+		RulesCount: len(rules.Rules),
+		UpdatedAt:  &updatedAt,
+	}, nil
+}
+
+// SetTeamCodeOwners заменяет CODEOWNERS команды; пустой content удаляет правила
+func (s *Storage) SetTeamCodeOwners(teamName, content string) (storage.TeamCodeOwners, error) {
+	const op = "storage.sqlite.SetTeamCodeOwners"
+
+	rules, err := codeowners.Parse(content)
+	if err != nil {
+		return storage.TeamCodeOwners{}, fmt.Errorf("%w: %s", storage.ErrInvalidCodeOwners, err)
+	}
+
+	var teamID int64
+	err = s.db.QueryRow(`SELECT id FROM teams WHERE name = ?`, teamName).Scan(&teamID)
+	if err == sql.ErrNoRows {
+		return storage.TeamCodeOwners{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return storage.TeamCodeOwners{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(rules.Rules) == 0 {
+		if _, err := s.db.Exec(`DELETE FROM team_codeowners WHERE team_id = ?`, teamID); err != nil {
+			return storage.TeamCodeOwners{}, fmt.Errorf("%s: %w", op, err)
+		}
+		return storage.TeamCodeOwners{TeamName: teamName, Content: content}, nil
+	}
+
+	_, err = s.db.Exec(`
+        INSERT INTO team_codeowners(team_id, content, updated_at)
+        VALUES(?, ?, CURRENT_TIMESTAMP)
+        ON CONFLICT(team_id) DO UPDATE SET
+            content    = excluded.content,
+            updated_at = excluded.updated_at`, teamID, content)
+	if err != nil {
+		return storage.TeamCodeOwners{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.GetTeamCodeOwners(teamName)
+}
+
+// teamCodeOwners разбирает CODEOWNERS команды; если его нет — пустой набор правил
+func teamCodeOwners(q querier, teamID int64) (codeowners.Ruleset, error) {
+	var content string
+	err := q.QueryRow(`SELECT content FROM team_codeowners WHERE team_id = ?`, teamID).Scan(&content)
+	if err == sql.ErrNoRows {
+		return codeowners.Ruleset{}, nil
+	}
+	if err != nil {
+		return codeowners.Ruleset{}, err
+	}
+
+	return codeowners.Parse(content)
+}
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"pr-service/internal/storage"
+)
+
+// DeclineReview — отказ назначенного ревьювера от ревью: замена ищется так же, как в ReassignReviewer,
+// а отказ запоминается, и на этот PR отказавшегося больше не назначают.
+// Замены нет — отказ всё равно принимается, а место уходит в очередь pending_reviewers (replacedBy пуст).
+func (s *Storage) DeclineReview(prID, userID, reason string) (storage.PullRequest, string, error) {
+	const op = "storage.sqlite.DeclineReview"
+
+	switch reason {
+	case storage.DeclineReasonConflict, storage.DeclineReasonNoExpertise, storage.DeclineReasonOverloaded:
+	default:
+		return storage.PullRequest{}, "", fmt.Errorf("%w: %q", storage.ErrInvalidDeclineReason, reason)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	replacedBy, err := s.replaceReviewer(tx, prID, userID, storage.EventReasonDeclined, userID)
+	switch {
+	case errors.Is(err, storage.ErrNoCandidate), errors.Is(err, storage.ErrNoSeniorCandidate):
+		if err := queueDeclinedSlot(tx, prID, userID); err != nil {
+			return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+	case err != nil:
+		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO pr_declines(pr_id, user_id, reason, replaced_by)
+        SELECT pr.id, u.id, ?, (SELECT id FROM users WHERE user_id = ?)
+        FROM pull_requests pr, users u
+        WHERE pr.pull_request_id = ? AND u.user_id = ?`,
+		reason, replacedBy, prID, userID)
+	if err != nil {
+		return storage.PullRequest{}, "", fmt.Errorf("%s: insert decline: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.PullRequest{}, "", fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	pr, err := s.getPullRequestByExternalID(prID)
+	if err != nil {
+		return storage.PullRequest{}, "", err
+	}
+	return pr, replacedBy, nil
+}
+
+// queueDeclinedSlot снимает отказавшегося с PR, когда заменить его некем:
+// как и при передаче ревью, место ждёт в очереди pending_reviewers
+func queueDeclinedSlot(tx *sql.Tx, prID, userID string) error {
+	var prIntID, userIntID int64
+	if err := tx.QueryRow(`
+        SELECT pr.id, u.id
+        FROM pull_requests pr, users u
+        WHERE pr.pull_request_id = ? AND u.user_id = ?`, prID, userID,
+	).Scan(&prIntID, &userIntID); err != nil {
+		return fmt.Errorf("query pr and reviewer: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM pr_reviewers WHERE pr_id = ? AND reviewer_id = ?`, prIntID, userIntID); err != nil {
+		return fmt.Errorf("delete reviewer from pr: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE pull_requests SET pending_reviewers = pending_reviewers + 1 WHERE id = ?`, prIntID); err != nil {
+		return fmt.Errorf("queue reviewer slot: %w", err)
+	}
+	if err := recordEvent(tx, prIntID, prEvent{
+		typ:    storage.EventReviewerRemoved,
+		userID: userIntID,
+		reason: storage.EventReasonDeclined,
+		actor:  userID,
+	}); err != nil {
+		return fmt.Errorf("record removal: %w", err)
+	}
+
+	return nil
+}
+
+// prDeclines — отказы от ревью PR в порядке поступления
+func prDeclines(q querier, prID string) ([]storage.ReviewDecline, error) {
+	rows, err := q.Query(`
+        SELECT u.user_id, d.reason, COALESCE(rb.user_id, ''), d.declined_at
+        FROM pr_declines d
+        JOIN pull_requests pr ON d.pr_id = pr.id
+        JOIN users u ON d.user_id = u.id
+        LEFT JOIN users rb ON d.replaced_by = rb.id
+        WHERE pr.pull_request_id = ?
+        ORDER BY d.declined_at, u.id`, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	declines := []storage.ReviewDecline{}
+	for rows.Next() {
+		var d storage.ReviewDecline
+		if err := rows.Scan(&d.UserID, &d.Reason, &d.ReplacedBy, &d.DeclinedAt); err != nil {
+			return nil, err
+		}
+		declines = append(declines, d)
+	}
+
+	return declines, rows.Err()
+}
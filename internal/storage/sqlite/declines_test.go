@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"slices"
+	"testing"
+
+	"pr-service/internal/storage"
+)
+
+func TestDeclineReviewQueuesSlotWithoutCandidate(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "first", "second")
+	mustCreatePR(t, s, "pr-1", "author", "first")
+
+	pr, replacedBy, err := s.DeclineReview("pr-1", "first", storage.DeclineReasonNoExpertise)
+	if err != nil {
+		t.Fatalf("decline first: %v", err)
+	}
+	if replacedBy != "second" || !slices.Equal(pr.AssignedReviewers, []string{"second"}) {
+		t.Fatalf("after first decline: replaced by %q, reviewers %v, want second", replacedBy, pr.AssignedReviewers)
+	}
+
+	// first отказался, автор не ревьюит — заменить second некем
+	pr, replacedBy, err = s.DeclineReview("pr-1", "second", storage.DeclineReasonOverloaded)
+	if err != nil {
+		t.Fatalf("decline second: %v", err)
+	}
+	if replacedBy != "" {
+		t.Errorf("replaced by = %q, want none", replacedBy)
+	}
+	if len(pr.AssignedReviewers) != 0 || pr.PendingReviewers != 1 {
+		t.Errorf("reviewers = %v, pending = %d, want slot queued", pr.AssignedReviewers, pr.PendingReviewers)
+	}
+	if len(pr.Declines) != 2 || pr.Declines[1].UserID != "second" || pr.Declines[1].ReplacedBy != "" {
+		t.Errorf("declines = %+v, want second recorded without replacement", pr.Declines)
+	}
+}
+
+func TestDeclineReviewHistoryReason(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "first", "second")
+	mustCreatePR(t, s, "pr-1", "author", "first")
+
+	if _, _, err := s.DeclineReview("pr-1", "first", storage.DeclineReasonConflict); err != nil {
+		t.Fatalf("decline first: %v", err)
+	}
+	if _, _, err := s.DeclineReview("pr-1", "second", storage.DeclineReasonOverloaded); err != nil {
+		t.Fatalf("decline second: %v", err)
+	}
+
+	events, err := s.GetPullRequestHistory("pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	var got []string
+	for _, e := range events {
+		if e.Type == storage.EventReviewerReplaced || e.Type == storage.EventReviewerRemoved {
+			got = append(got, e.Type+"/"+e.Reason+"/"+e.Actor)
+		}
+	}
+	want := []string{
+		storage.EventReviewerReplaced + "/" + storage.EventReasonDeclined + "/first",
+		storage.EventReviewerRemoved + "/" + storage.EventReasonDeclined + "/second",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
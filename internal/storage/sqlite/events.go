@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"fmt"
+	"pr-service/internal/storage"
+)
+
+// prEvent — событие истории PR; нулевые id пользователей пишутся как NULL
+type prEvent struct {
+	typ           string
+	userID        int64
+	replacementID int64
+	reason        string
+	actor         string
+}
+
+// recordEvent дописывает событие в историю PR
+func recordEvent(q querier, prIntID int64, e prEvent) error {
+	_, err := q.Exec(`
+        INSERT INTO pr_events(pr_id, type, user_id, replacement_id, reason, actor)
+        VALUES(?, ?, ?, ?, ?, ?)`,
+		prIntID, e.typ, nullID(e.userID), nullID(e.replacementID), e.reason, e.actor)
+	return err
+}
+
+// nullID — 0 как NULL для необязательных ссылок на users
+func nullID(id int64) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// GetPullRequestHistory возвращает историю PR в порядке событий.
+// У PR, созданных до появления истории, события начинаются с первого изменения после обновления.
+func (s *Storage) GetPullRequestHistory(prID string) ([]storage.PREvent, error) {
+	const op = "storage.sqlite.GetPullRequestHistory"
+
+	prIntID, _, err := prStatus(s.db, prID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.Query(`
+        SELECT e.id, e.type, COALESCE(u.user_id, ''), COALESCE(rp.user_id, ''), e.reason, e.actor, e.created_at
+        FROM pr_events e
+        LEFT JOIN users u ON e.user_id = u.id
+        LEFT JOIN users rp ON e.replacement_id = rp.id
+        WHERE e.pr_id = ?
+        ORDER BY e.id`, prIntID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	events := []storage.PREvent{}
+	for rows.Next() {
+		var e storage.PREvent
+		if err := rows.Scan(&e.ID, &e.Type, &e.UserID, &e.ReplacementID, &e.Reason, &e.Actor, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows err: %w", op, err)
+	}
+
+	return events, nil
+}
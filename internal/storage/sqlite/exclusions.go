@@ -0,0 +1,216 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"pr-service/internal/storage"
+)
+
+// AddReviewerExclusion запрещает паре пользователей ревьюить PR друг друга.
+// Повторное добавление пары обновляет причину.
+func (s *Storage) AddReviewerExclusion(userID, otherUserID, reason string) (storage.ReviewerExclusion, error) {
+	const op = "storage.sqlite.AddReviewerExclusion"
+
+	if userID == otherUserID {
+		return storage.ReviewerExclusion{}, fmt.Errorf("%w: user cannot be excluded from themselves", storage.ErrInvalidExclusion)
+	}
+
+	a, b, err := exclusionPair(s.db, userID, otherUserID)
+	if err != nil {
+		return storage.ReviewerExclusion{}, err
+	}
+
+	_, err = s.db.Exec(`
+        INSERT INTO reviewer_exclusions(user_a, user_b, reason)
+        VALUES(?, ?, ?)
+        ON CONFLICT(user_a, user_b) DO UPDATE SET reason = excluded.reason`, a, b, reason)
+	if err != nil {
+		return storage.ReviewerExclusion{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var createdAt sql.NullTime
+	err = s.db.QueryRow(`
+        SELECT created_at FROM reviewer_exclusions
+        WHERE user_a = ? AND user_b = ?`, a, b,
+	).Scan(&createdAt)
+	if err != nil {
+		return storage.ReviewerExclusion{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ex := storage.ReviewerExclusion{UserID: userID, OtherUserID: otherUserID, Reason: reason}
+	if createdAt.Valid {
+		t := createdAt.Time
+		ex.CreatedAt = &t
+	}
+	return ex, nil
+}
+
+// RemoveReviewerExclusion снимает запрет с пары; порядок пользователей не важен
+func (s *Storage) RemoveReviewerExclusion(userID, otherUserID string) error {
+	const op = "storage.sqlite.RemoveReviewerExclusion"
+
+	a, b, err := exclusionPair(s.db, userID, otherUserID)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.Exec(`DELETE FROM reviewer_exclusions WHERE user_a = ? AND user_b = ?`, a, b)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, _ := res.RowsAffected()
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListReviewerExclusions возвращает пары пользователя (UserID — он сам) или все пары, если userID пуст
+func (s *Storage) ListReviewerExclusions(userID string) ([]storage.ReviewerExclusion, error) {
+	const op = "storage.sqlite.ListReviewerExclusions"
+
+	if userID != "" {
+		var tmp int
+		err := s.db.QueryRow(`SELECT 1 FROM users WHERE user_id = ?`, userID).Scan(&tmp)
+		if err == sql.ErrNoRows {
+			return nil, storage.ErrNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	rows, err := s.db.Query(`
+        SELECT ua.user_id, ub.user_id, e.reason, e.created_at
+        FROM reviewer_exclusions e
+        JOIN users ua ON e.user_a = ua.id
+        JOIN users ub ON e.user_b = ub.id
+        WHERE ? = '' OR ua.user_id = ? OR ub.user_id = ?
+        ORDER BY ua.user_id, ub.user_id`, userID, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	exclusions := []storage.ReviewerExclusion{}
+	for rows.Next() {
+		var (
+			ex        storage.ReviewerExclusion
+			createdAt sql.NullTime
+		)
+		if err := rows.Scan(&ex.UserID, &ex.OtherUserID, &ex.Reason, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		// запрошенный пользователь всегда слева
+		if userID != "" && ex.OtherUserID == userID {
+			ex.UserID, ex.OtherUserID = ex.OtherUserID, ex.UserID
+		}
+		if createdAt.Valid {
+			t := createdAt.Time
+			ex.CreatedAt = &t
+		}
+		exclusions = append(exclusions, ex)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exclusions, nil
+}
+
+// exclusionPair — внутренние id пары в порядке хранения (меньший первым)
+func exclusionPair(q querier, userID, otherUserID string) (int64, int64, error) {
+	ids, err := userIntIDs(q, []string{userID, otherUserID})
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(ids) != 2 {
+		return 0, 0, storage.ErrNotFound
+	}
+
+	a, b := ids[0], ids[1]
+	if a > b {
+		a, b = b, a
+	}
+	return a, b, nil
+}
+
+// userIntIDs переводит внешние user_id во внутренние id; неизвестные пропускаются
+func userIntIDs(q querier, userIDs []string) ([]int64, error) {
+	ids := make([]int64, 0, len(userIDs))
+	for _, uid := range userIDs {
+		var id int64
+		err := q.QueryRow(`SELECT id FROM users WHERE user_id = ?`, uid).Scan(&id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// exclusionPartners — с кем у пользователя пара-исключение
+func exclusionPartners(q querier, userIntID int64) (map[int64]struct{}, error) {
+	rows, err := q.Query(`
+        SELECT user_b FROM reviewer_exclusions WHERE user_a = ?
+        UNION
+        SELECT user_a FROM reviewer_exclusions WHERE user_b = ?`, userIntID, userIntID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	partners := make(map[int64]struct{})
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		partners[id] = struct{}{}
+	}
+
+	return partners, rows.Err()
+}
+
+// excludedForPR — кого нельзя назначать на PR: исключения самого PR, отказавшиеся от него и пары с автором
+func excludedForPR(q querier, prIntID, authorIntID int64) (map[int64]struct{}, error) {
+	excluded, err := exclusionPartners(q, authorIntID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(`
+        SELECT user_id FROM pr_excluded_reviewers WHERE pr_id = ?
+        UNION
+        SELECT user_id FROM pr_declines WHERE pr_id = ?`, prIntID, prIntID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		excluded[id] = struct{}{}
+	}
+
+	return excluded, rows.Err()
+}
+
+// prExcludedUserIDs — user_id исключённых для PR по внешнему pull_request_id
+func prExcludedUserIDs(q querier, prID string) ([]string, error) {
+	return stringColumn(q, `
+        SELECT u.user_id
+        FROM pr_excluded_reviewers e
+        JOIN users u ON e.user_id = u.id
+        JOIN pull_requests pr ON e.pr_id = pr.id
+        WHERE pr.pull_request_id = ?
+        ORDER BY u.user_id`, prID)
+}
@@ -0,0 +1,285 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"pr-service/internal/storage"
+	"slices"
+	"time"
+)
+
+// Жизненный цикл PR:
+//
+//	DRAFT --ready--> OPEN --merge--> MERGED
+//	DRAFT, OPEN --close--> CLOSED --reopen--> DRAFT или OPEN (каким PR был до закрытия)
+
+// MarkReadyForReview переводит черновик в OPEN и назначает ревьюверов так же, как при создании PR:
+// явно указанные при создании черновика проверяются заново и занимают места первыми.
+// Ставшие с тех пор недоступными пропускаются: их места добираются автоназначением,
+// а сами они возвращаются в DroppedReviewers с предупреждением WarningRequestedReviewerUnavailable.
+func (s *Storage) MarkReadyForReview(prID string) (storage.PullRequest, error) {
+	const op = "storage.sqlite.MarkReadyForReview"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	prIntID, status, err := prStatus(tx, prID)
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	switch status {
+	case "MERGED":
+		return storage.PullRequest{}, storage.ErrPRMerged
+	case "CLOSED":
+		return storage.PullRequest{}, storage.ErrPRClosed
+	case "OPEN":
+		return storage.PullRequest{}, fmt.Errorf("%w: pull request is already open", storage.ErrInvalidTransition)
+	}
+
+	// параметры выбора сохранены при создании черновика
+	newPR := storage.NewPullRequest{ID: prID}
+	if err := tx.QueryRow(`
+        SELECT au.user_id
+        FROM pull_requests pr
+        JOIN users au ON pr.author_id = au.id
+        WHERE pr.id = ?`, prIntID,
+	).Scan(&newPR.AuthorID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: query author: %w", op, err)
+	}
+	if newPR.ChangedFiles, err = stringColumn(tx, `SELECT path FROM pr_files WHERE pr_id = ? ORDER BY path`, prIntID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: query files: %w", op, err)
+	}
+	if newPR.Labels, err = prLabels(tx, prID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: query labels: %w", op, err)
+	}
+	if newPR.ExcludedReviewers, err = prExcludedUserIDs(tx, prID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: query excluded reviewers: %w", op, err)
+	}
+	if newPR.RequestedReviewers, err = prRequestedUserIDs(tx, prID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: query requested reviewers: %w", op, err)
+	}
+
+	req, _, err := newAssignmentRequest(tx, newPR)
+	var invalid *storage.InvalidReviewersError
+	if errors.As(err, &invalid) {
+		newPR.RequestedReviewers = slices.DeleteFunc(newPR.RequestedReviewers, func(uid string) bool {
+			return slices.ContainsFunc(invalid.Reviewers, func(r storage.InvalidReviewer) bool { return r.UserID == uid })
+		})
+		req, _, err = newAssignmentRequest(tx, newPR)
+	}
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	plan, err := s.planAssignment(tx, req)
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if invalid != nil {
+		plan.warnings = append(plan.warnings, storage.WarningRequestedReviewerUnavailable)
+	}
+
+	if err := recordEvent(tx, prIntID, prEvent{typ: storage.EventReadyForReview}); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, a := range plan.picked {
+		if err := insertAssignment(tx, prIntID, a); err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if _, err := tx.Exec(`
+        UPDATE pull_requests
+        SET status = 'OPEN', ready_at = CURRENT_TIMESTAMP, pending_reviewers = ?
+        WHERE id = ?`, plan.pending, prIntID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM pr_requested_reviewers WHERE pr_id = ?`, prIntID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pr, err := s.getPullRequestByExternalID(prID)
+	if err != nil {
+		return storage.PullRequest{}, err
+	}
+	pr.Warnings = plan.warnings
+	if invalid != nil {
+		pr.DroppedReviewers = invalid.Reviewers
+	}
+	return pr, nil
+}
+
+// prRequestedUserIDs — кого автор черновика указал ревьюверами; после перевода в OPEN список пуст
+func prRequestedUserIDs(q querier, prID string) ([]string, error) {
+	return stringColumn(q, `
+        SELECT u.user_id
+        FROM pr_requested_reviewers r
+        JOIN users u ON r.user_id = u.id
+        JOIN pull_requests pr ON r.pr_id = pr.id
+        WHERE pr.pull_request_id = ?
+        ORDER BY u.id`, prID)
+}
+
+// ClosePullRequest закрывает PR без merge (идемпотентно, как merge).
+// Ревьюверы остаются в PR, но ревью больше не считается открытым, поэтому их места в очереди раздаются другим PR.
+func (s *Storage) ClosePullRequest(prID string) (storage.PullRequest, error) {
+	const op = "storage.sqlite.ClosePullRequest"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	prIntID, status, err := prStatus(tx, prID)
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	switch status {
+	case "MERGED":
+		return storage.PullRequest{}, storage.ErrPRMerged
+	case "DRAFT", "OPEN":
+		if _, err := tx.Exec(`
+            UPDATE pull_requests
+            SET status = 'CLOSED', closed_at = CURRENT_TIMESTAMP
+            WHERE id = ?`, prIntID); err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := recordEvent(tx, prIntID, prEvent{typ: storage.EventClosed}); err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := s.fillPendingReviewers(tx); err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.getPullRequestByExternalID(prID)
+}
+
+// ReopenPullRequest возвращает закрытый PR в статус, который был до закрытия:
+// черновик — в DRAFT, остальные — в OPEN с прежними ревьюверами.
+// Время закрытия не считается временем ревью: назначения начинаются заново, напоминания сбрасываются.
+// Ревьюверы, ставшие за это время неактивными или отсутствующими, передаются другим, как при деактивации.
+func (s *Storage) ReopenPullRequest(prID string) (storage.PullRequest, error) {
+	const op = "storage.sqlite.ReopenPullRequest"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	prIntID, status, err := prStatus(tx, prID)
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	switch status {
+	case "MERGED":
+		return storage.PullRequest{}, storage.ErrPRMerged
+	case "DRAFT", "OPEN":
+		return storage.PullRequest{}, fmt.Errorf("%w: pull request is not closed", storage.ErrInvalidTransition)
+	}
+
+	if _, err := tx.Exec(`
+        UPDATE pull_requests
+        SET status = CASE WHEN ready_at IS NULL THEN 'DRAFT' ELSE 'OPEN' END, closed_at = NULL
+        WHERE id = ?`, prIntID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := recordEvent(tx, prIntID, prEvent{typ: storage.EventReopened}); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(`UPDATE pr_reviewers SET assigned_at = CURRENT_TIMESTAMP WHERE pr_id = ?`, prIntID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: reset assigned_at: %w", op, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM pr_reminders WHERE pr_id = ?`, prIntID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: reset reminders: %w", op, err)
+	}
+	if err := s.handOverUnavailableReviewers(tx, prIntID); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// пока PR был закрыт, свободные места могли появиться — пробуем занять его ожидающие
+	if err := s.fillPendingReviewers(tx); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.getPullRequestByExternalID(prID)
+}
+
+// handOverUnavailableReviewers передаёт ревью PR тех, кто сейчас неактивен или отсутствует:
+// пока PR был закрыт, handOverReviews его не видел
+func (s *Storage) handOverUnavailableReviewers(tx *sql.Tx, prIntID int64) error {
+	now := sqlTime(time.Now())
+	rows, err := tx.Query(`
+        SELECT u.id, u.team_id, t.name, u.is_active
+        FROM pr_reviewers r
+        JOIN users u ON r.reviewer_id = u.id
+        JOIN teams t ON u.team_id = t.id
+        WHERE r.pr_id = ?
+          AND (u.is_active = 0 OR EXISTS (SELECT 1 FROM user_absences a
+                                          WHERE a.user_id = u.id AND a.starts_at <= ? AND a.ends_at > ?))
+        ORDER BY u.id`, prIntID, now, now)
+	if err != nil {
+		return fmt.Errorf("query unavailable reviewers: %w", err)
+	}
+
+	type reviewer struct {
+		id, teamID int64
+		teamName   string
+		reason     string
+	}
+	var unavailable []reviewer
+	for rows.Next() {
+		var r reviewer
+		var isActive bool
+		if err := rows.Scan(&r.id, &r.teamID, &r.teamName, &isActive); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan unavailable reviewer: %w", err)
+		}
+		r.reason = storage.EventReasonAbsent
+		if !isActive {
+			r.reason = storage.EventReasonDeactivated
+		}
+		unavailable = append(unavailable, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("unavailable reviewers rows err: %w", err)
+	}
+
+	// handOverReviews берёт все открытые ревью ушедшего — других у него и так быть не должно
+	for _, r := range unavailable {
+		if _, err := s.handOverReviews(tx, r.teamID, r.teamName, []int64{r.id}, r.reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prStatus возвращает внутренний id и статус PR
+func prStatus(q querier, prID string) (int64, string, error) {
+	var id int64
+	var status string
+	err := q.QueryRow(`SELECT id, status FROM pull_requests WHERE pull_request_id = ?`, prID).Scan(&id, &status)
+	if err == sql.ErrNoRows {
+		return 0, "", storage.ErrNotFound
+	}
+	return id, status, err
+}
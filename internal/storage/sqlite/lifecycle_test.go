@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReopenHandsOverUnavailableReviewers(t *testing.T) {
+	tests := []struct {
+		name        string
+		leave       func(t *testing.T, s *Storage)
+		members     []string
+		wantAssign  []string
+		wantPending int
+	}{
+		{
+			name: "deactivated",
+			leave: func(t *testing.T, s *Storage) {
+				if _, err := s.SetUserIsActive("first", false); err != nil {
+					t.Fatalf("deactivate: %v", err)
+				}
+			},
+			members:    []string{"author", "first", "second"},
+			wantAssign: []string{"second"},
+		},
+		{
+			name: "absent",
+			leave: func(t *testing.T, s *Storage) {
+				now := time.Now()
+				if _, err := s.SetUserAbsence("first", now.Add(-time.Minute), now.Add(time.Hour)); err != nil {
+					t.Fatalf("set absence: %v", err)
+				}
+			},
+			members:    []string{"author", "first", "second"},
+			wantAssign: []string{"second"},
+		},
+		{
+			name: "nobody left",
+			leave: func(t *testing.T, s *Storage) {
+				if _, err := s.SetUserIsActive("first", false); err != nil {
+					t.Fatalf("deactivate: %v", err)
+				}
+			},
+			members:     []string{"author", "first"},
+			wantAssign:  []string{},
+			wantPending: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t)
+			mustCreateTeam(t, s, "backend", 1, tt.members...)
+			mustCreatePR(t, s, "pr-1", "author", "first")
+
+			if _, err := s.ClosePullRequest("pr-1"); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+			// закрытый PR передача ревью не трогает
+			tt.leave(t, s)
+			if got := mustGetPR(t, s, "pr-1").AssignedReviewers; !slices.Equal(got, []string{"first"}) {
+				t.Fatalf("reviewers while closed = %v, want [first]", got)
+			}
+
+			pr, err := s.ReopenPullRequest("pr-1")
+			if err != nil {
+				t.Fatalf("reopen: %v", err)
+			}
+			if !slices.Equal(pr.AssignedReviewers, tt.wantAssign) || pr.PendingReviewers != tt.wantPending {
+				t.Errorf("reviewers = %v, pending = %d, want %v, %d",
+					pr.AssignedReviewers, pr.PendingReviewers, tt.wantAssign, tt.wantPending)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package sqlite
+
+import (
+	"encoding/base64"
+	"fmt"
+	"pr-service/internal/storage"
+	"strconv"
+	"strings"
+)
+
+// GetPullRequest возвращает PR по внешнему id
+func (s *Storage) GetPullRequest(prID string) (storage.PullRequest, error) {
+	return s.getPullRequestByExternalID(prID)
+}
+
+// ListPullRequests возвращает PR по фильтру, новые первыми.
+// Пагинация по курсору: курсор — последний отданный PR, поэтому новые PR не сдвигают следующие страницы.
+func (s *Storage) ListPullRequests(filter storage.PullRequestFilter) (storage.PullRequestPage, error) {
+	const op = "storage.sqlite.ListPullRequests"
+
+	switch filter.Status {
+	case "", "DRAFT", "OPEN", "MERGED", "CLOSED":
+	default:
+		return storage.PullRequestPage{}, fmt.Errorf("%w: unknown status %q", storage.ErrInvalidFilter, filter.Status)
+	}
+	if filter.Limit < 0 {
+		return storage.PullRequestPage{}, fmt.Errorf("%w: limit must not be negative", storage.ErrInvalidFilter)
+	}
+	limit := filter.Limit
+	if limit == 0 {
+		limit = storage.DefaultPageSize
+	}
+	limit = min(limit, storage.MaxPageSize)
+
+	var where []string
+	var args []any
+	if filter.Cursor != "" {
+		afterID, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return storage.PullRequestPage{}, fmt.Errorf("%w: invalid cursor", storage.ErrInvalidFilter)
+		}
+		where, args = append(where, "pr.id < ?"), append(args, afterID)
+	}
+	if filter.Status != "" {
+		where, args = append(where, "pr.status = ?"), append(args, filter.Status)
+	}
+	if filter.AuthorID != "" {
+		where, args = append(where, "au.user_id = ?"), append(args, filter.AuthorID)
+	}
+	if filter.TeamName != "" {
+		where, args = append(where, "t.name = ?"), append(args, filter.TeamName)
+	}
+	if filter.ReviewerID != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM pr_reviewers r JOIN users ru ON r.reviewer_id = ru.id
+                                       WHERE r.pr_id = pr.id AND ru.user_id = ?)`)
+		args = append(args, filter.ReviewerID)
+	}
+	// даты хранятся строками CURRENT_TIMESTAMP, сравниваем в том же виде
+	if filter.CreatedFrom != nil {
+		where, args = append(where, "pr.created_at >= ?"), append(args, sqlTime(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		where, args = append(where, "pr.created_at < ?"), append(args, sqlTime(*filter.CreatedTo))
+	}
+	if filter.MergedFrom != nil {
+		where, args = append(where, "pr.merged_at >= ?"), append(args, sqlTime(*filter.MergedFrom))
+	}
+	if filter.MergedTo != nil {
+		where, args = append(where, "pr.merged_at < ?"), append(args, sqlTime(*filter.MergedTo))
+	}
+
+	query := `
+        SELECT pr.id, pr.pull_request_id
+        FROM pull_requests pr
+        JOIN users au ON pr.author_id = au.id
+        JOIN teams t ON au.team_id = t.id`
+	if len(where) > 0 {
+		query += "\n        WHERE " + strings.Join(where, " AND ")
+	}
+	// на одну строку больше — чтобы понять, есть ли следующая страница
+	query += "\n        ORDER BY pr.id DESC\n        LIMIT ?"
+	args = append(args, limit+1)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return storage.PullRequestPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	type row struct {
+		id    int64
+		extID string
+	}
+	var found []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.extID); err != nil {
+			return storage.PullRequestPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		found = append(found, r)
+	}
+	if err := rows.Err(); err != nil {
+		return storage.PullRequestPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+
+	page := storage.PullRequestPage{PullRequests: make([]storage.PullRequest, 0, min(len(found), limit))}
+	if len(found) > limit {
+		found = found[:limit]
+		page.NextCursor = encodeCursor(found[limit-1].id)
+	}
+	for _, r := range found {
+		pr, err := s.getPullRequestByExternalID(r.extID)
+		if err != nil {
+			return storage.PullRequestPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		page.PullRequests = append(page.PullRequests, pr)
+	}
+
+	return page, nil
+}
+
+// encodeCursor и decodeCursor: курсор непрозрачен для клиента, внутри — id последнего PR страницы
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(raw), 10, 64)
+}
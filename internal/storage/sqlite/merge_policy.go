@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"pr-service/internal/storage"
+	"slices"
+)
+
+// unmetMergeConditions проверяет PR по условиям merge команды автора; пусто — merge разрешён.
+// Учитываются последние итоги текущих ревьюверов: итог заменённого ревьювера уходит вместе с ним.
+func unmetMergeConditions(q querier, prIntID, authorTeamID int64) ([]storage.UnmetCondition, error) {
+	settings, err := teamSettings(q, authorTeamID)
+	if err != nil {
+		return nil, err
+	}
+	policy := settings.MergePolicy
+	if policy == (storage.MergePolicy{}) {
+		return nil, nil
+	}
+
+	rows, err := q.Query(`
+        SELECT u.user_id, u.seniority, COALESCE(r.verdict, '')
+        FROM pr_reviewers r
+        JOIN users u ON r.reviewer_id = u.id
+        WHERE r.pr_id = ?
+        ORDER BY u.id`, prIntID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type review struct {
+		userID, seniority, verdict string
+	}
+	var reviews []review
+	for rows.Next() {
+		var r review
+		if err := rows.Scan(&r.userID, &r.seniority, &r.verdict); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	approvals := 0
+	changesRequested := []string{}
+	for _, r := range reviews {
+		switch r.verdict {
+		case storage.VerdictApproved:
+			approvals++
+		case storage.VerdictChangesRequested:
+			changesRequested = append(changesRequested, r.userID)
+		}
+	}
+
+	var unmet []storage.UnmetCondition
+	if approvals < policy.RequiredApprovals {
+		unmet = append(unmet, storage.UnmetCondition{
+			Code:     storage.MergeConditionApprovals,
+			Required: policy.RequiredApprovals,
+			Actual:   approvals,
+		})
+	}
+	if policy.BlockOnChangesRequested && len(changesRequested) > 0 {
+		unmet = append(unmet, storage.UnmetCondition{
+			Code:    storage.MergeConditionChangesRequested,
+			UserIDs: changesRequested,
+		})
+	}
+	if policy.RequireSeniorOrOwnerApproval {
+		// владельцы изменённых путей — по CODEOWNERS команды автора, как при назначении
+		rules, err := teamCodeOwners(q, authorTeamID)
+		if err != nil {
+			return nil, err
+		}
+		files, err := stringColumn(q, `SELECT path FROM pr_files WHERE pr_id = ? ORDER BY path`, prIntID)
+		if err != nil {
+			return nil, err
+		}
+		owners := rules.OwnersOf(files)
+
+		approved := slices.ContainsFunc(reviews, func(r review) bool {
+			return r.verdict == storage.VerdictApproved &&
+				(r.seniority == storage.SenioritySenior || slices.Contains(owners, r.userID))
+		})
+		if !approved {
+			unmet = append(unmet, storage.UnmetCondition{Code: storage.MergeConditionSeniorOrOwner})
+		}
+	}
+
+	return unmet, nil
+}
@@ -0,0 +1,236 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"pr-service/internal/selector"
+	"pr-service/internal/storage"
+)
+
+// GetPendingQueue возвращает открытые PR, которым не хватает ревьюверов, старые первыми.
+// Пустой teamName — очередь по всем командам.
+func (s *Storage) GetPendingQueue(teamName string) ([]storage.PendingPullRequest, error) {
+	const op = "storage.sqlite.GetPendingQueue"
+
+	if teamName != "" {
+		var tmp int
+		err := s.db.QueryRow(`SELECT 1 FROM teams WHERE name = ?`, teamName).Scan(&tmp)
+		if err == sql.ErrNoRows {
+			return nil, storage.ErrNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	rows, err := s.db.Query(`
+        SELECT pr.id, pr.pull_request_id, pr.name, au.user_id, t.name, pr.pending_reviewers, pr.created_at
+        FROM pull_requests pr
+        JOIN users au ON pr.author_id = au.id
+        JOIN teams t ON au.team_id = t.id
+        WHERE pr.status = 'OPEN' AND pr.pending_reviewers > 0
+          AND (? = '' OR t.name = ?)
+        ORDER BY pr.created_at, pr.id`, teamName, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var intIDs []int64
+	queue := []storage.PendingPullRequest{}
+	for rows.Next() {
+		var (
+			intID     int64
+			p         storage.PendingPullRequest
+			createdAt sql.NullTime
+		)
+		if err := rows.Scan(&intID, &p.ID, &p.Name, &p.AuthorID, &p.TeamName, &p.PendingReviewers, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if createdAt.Valid {
+			t := createdAt.Time
+			p.CreatedAt = &t
+		}
+		intIDs = append(intIDs, intID)
+		queue = append(queue, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+
+	for i, intID := range intIDs {
+		reviewers, err := assignedUserIDs(s.db, intID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		queue[i].AssignedReviewers = reviewers
+	}
+
+	return queue, nil
+}
+
+// fillPendingReviewers раздаёт ожидающие места ревьюверов открытых PR (старые первыми)
+// тем участникам команды автора, у кого появился запас по лимиту.
+// Если команда требует senior, а на PR его нет, одно место отдаётся только senior'у.
+func (s *Storage) fillPendingReviewers(q querier) error {
+	type pendingPR struct {
+		id       int64
+		extID    string
+		authorID int64
+		teamID   int64
+		teamName string
+		pending  int
+	}
+
+	rows, err := q.Query(`
+        SELECT pr.id, pr.pull_request_id, pr.author_id, au.team_id, t.name, pr.pending_reviewers
+        FROM pull_requests pr
+        JOIN users au ON pr.author_id = au.id
+        JOIN teams t ON au.team_id = t.id
+        WHERE pr.status = 'OPEN' AND pr.pending_reviewers > 0
+        ORDER BY pr.created_at, pr.id`)
+	if err != nil {
+		return fmt.Errorf("query pending prs: %w", err)
+	}
+	defer rows.Close()
+
+	var queue []pendingPR
+	for rows.Next() {
+		var p pendingPR
+		if err := rows.Scan(&p.id, &p.extID, &p.authorID, &p.teamID, &p.teamName, &p.pending); err != nil {
+			return fmt.Errorf("scan pending pr: %w", err)
+		}
+		queue = append(queue, p)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("pending prs rows err: %w", err)
+	}
+	rows.Close()
+
+	// кандидаты по командам; нагрузка обновляется по мере раздачи мест
+	teams := make(map[int64][]selector.Candidate)
+
+	for _, p := range queue {
+		candidates, ok := teams[p.teamID]
+		if !ok {
+			candidates, err = activeCandidates(q, p.teamID)
+			if err != nil {
+				return fmt.Errorf("query candidates: %w", err)
+			}
+			teams[p.teamID] = candidates
+		}
+
+		unavailable, err := unavailableForPR(q, p.id, p.authorID)
+		if err != nil {
+			return fmt.Errorf("query assigned reviewers: %w", err)
+		}
+
+		settings, err := teamSettings(q, p.teamID)
+		if err != nil {
+			return fmt.Errorf("team settings: %w", err)
+		}
+
+		labels, err := prLabels(q, p.extID)
+		if err != nil {
+			return fmt.Errorf("query labels: %w", err)
+		}
+
+		wantSenior, err := seniorWanted(q, p.teamID, p.id, 0)
+		if err != nil {
+			return fmt.Errorf("senior policy: %w", err)
+		}
+
+		free, _ := splitByCapacity(without(candidates, unavailable))
+		free, err = withAuthorHistory(q, free, p.authorID)
+		if err != nil {
+			return fmt.Errorf("query author history: %w", err)
+		}
+		sel := s.selectors.ForTeam(p.teamName, settings.Strategy)
+		rank := replacementRank(labels, wantSenior)
+		seed, rnd := s.draw()
+
+		// пока у PR нет требуемого senior'а, одно место ждёт именно его
+		var picked []selector.Candidate
+		n := p.pending
+		if wantSenior {
+			picked = selector.SelectTiered(sel, rnd, p.teamName, rankTiers(seniors(free), rank), 1)
+			n--
+			if len(picked) > 0 {
+				free = without(free, map[int64]struct{}{picked[0].ID: {}})
+			}
+		}
+		picked = append(picked, selector.SelectTiered(sel, rnd, p.teamName, rankTiers(free, rank), n)...)
+		if len(picked) == 0 {
+			continue
+		}
+
+		for _, c := range picked {
+			a := assignment{
+				candidate: c,
+				source:    storage.ReviewerSourceTeam,
+				teamID:    p.teamID,
+				matched:   matchedLabels(c.Skills, labels),
+				strategy:  s.selectors.NameForTeam(p.teamName, settings.Strategy),
+				seed:      seed,
+			}
+			if err := insertAssignment(q, p.id, a); err != nil {
+				return fmt.Errorf("insert reviewer: %w", err)
+			}
+			addOpenReviews(candidates, c.ID, 1)
+		}
+
+		if _, err := q.Exec(
+			`UPDATE pull_requests SET pending_reviewers = pending_reviewers - ? WHERE id = ?`,
+			len(picked), p.id,
+		); err != nil {
+			return fmt.Errorf("update pending reviewers: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// assignedReviewerIDs — внутренние id назначенных на PR ревьюверов
+func assignedReviewerIDs(q querier, prIntID int64) (map[int64]struct{}, error) {
+	rows, err := q.Query(`SELECT reviewer_id FROM pr_reviewers WHERE pr_id = ?`, prIntID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assigned := make(map[int64]struct{})
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		assigned[id] = struct{}{}
+	}
+
+	return assigned, rows.Err()
+}
+
+// assignedUserIDs — внешние user_id назначенных на PR ревьюверов
+func assignedUserIDs(q querier, prIntID int64) ([]string, error) {
+	rows, err := q.Query(`
+        SELECT u.user_id
+        FROM pr_reviewers r
+        JOIN users u ON r.reviewer_id = u.id
+        WHERE r.pr_id = ?`, prIntID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviewers := make([]string, 0, 2)
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, uid)
+	}
+
+	return reviewers, rows.Err()
+}
@@ -0,0 +1,181 @@
+package sqlite
+
+import (
+	"fmt"
+	"pr-service/internal/selector"
+	"pr-service/internal/storage"
+	"slices"
+)
+
+// PreviewAssignment подбирает ревьюверов так же, как CreatePullRequestWithAutoAssign, но ничего не записывает.
+// Вместе с выбором возвращает пул кандидатов и тех, кто в него не попал, с причинами.
+func (s *Storage) PreviewAssignment(newPR storage.NewPullRequest) (storage.AssignmentPreview, error) {
+	const op = "storage.sqlite.PreviewAssignment"
+
+	// транзакция только ради согласованного чтения; всегда откатывается
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.AssignmentPreview{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	req, prExcluded, err := newAssignmentRequest(tx, newPR)
+	if err != nil {
+		return storage.AssignmentPreview{}, fmt.Errorf("%s: %w", op, err)
+	}
+	req.preview = true
+
+	plan, err := s.planAssignment(tx, req)
+	if err != nil {
+		return storage.AssignmentPreview{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	settings, err := teamSettings(tx, req.teamID)
+	if err != nil {
+		return storage.AssignmentPreview{}, fmt.Errorf("%s: %w", op, err)
+	}
+	rules, err := teamCodeOwners(tx, req.teamID)
+	if err != nil {
+		return storage.AssignmentPreview{}, fmt.Errorf("%s: %w", op, err)
+	}
+	owners := rules.OwnersOf(req.changedFiles)
+
+	preview := storage.AssignmentPreview{
+		TeamName:         req.teamName,
+		Strategy:         plan.strategy,
+		SelectionSeed:    plan.seed,
+		Candidates:       []storage.PreviewCandidate{},
+		Excluded:         []storage.ExcludedCandidate{},
+		Reviewers:        make([]storage.ReviewerAssignment, 0, len(plan.picked)),
+		PendingReviewers: plan.pending,
+		Warnings:         plan.warnings,
+	}
+
+	// пул: команда автора и, если разрешено, запасные команды — в том же порядке, что и при выборе
+	teams := []string{req.teamName}
+	if settings.AllowCrossTeamFallback {
+		teams = append(teams, settings.FallbackTeams...)
+	}
+
+	teamOf := make(map[int64]string)
+	for _, teamName := range teams {
+		var teamID int64
+		if err := tx.QueryRow(`SELECT id FROM teams WHERE name = ?`, teamName).Scan(&teamID); err != nil {
+			continue // команду удалили или переименовали — при выборе её тоже пропускают
+		}
+
+		candidates, err := activeCandidates(tx, teamID)
+		if err != nil {
+			return storage.AssignmentPreview{}, fmt.Errorf("%s: %w", op, err)
+		}
+		active := make(map[int64]selector.Candidate, len(candidates))
+		for _, c := range candidates {
+			active[c.ID] = c
+		}
+
+		members, err := teamMemberStates(tx, teamID)
+		if err != nil {
+			return storage.AssignmentPreview{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, m := range members {
+			if _, seen := teamOf[m.id]; seen {
+				continue
+			}
+			teamOf[m.id] = teamName
+
+			// активный, но не попавший в activeCandidates, сейчас отсутствует
+			c, ok := active[m.id]
+			reason := ""
+			switch {
+			case m.id == req.authorID:
+				reason = storage.ExclusionReasonAuthor
+			case slices.Contains(prExcluded, m.id):
+				reason = storage.ExclusionReasonExcluded
+			case hasID(req.excluded, m.id):
+				reason = storage.ExclusionReasonPair
+			case !m.active:
+				reason = storage.ExclusionReasonInactive
+			case !ok:
+				reason = storage.ExclusionReasonAbsent
+			case c.AtCapacity():
+				reason = storage.ExclusionReasonAtCapacity
+			}
+			if reason != "" {
+				preview.Excluded = append(preview.Excluded, storage.ExcludedCandidate{
+					UserID:   m.userID,
+					TeamName: teamName,
+					Reason:   reason,
+				})
+				continue
+			}
+
+			preview.Candidates = append(preview.Candidates, storage.PreviewCandidate{
+				UserID:         c.UserID,
+				TeamName:       teamName,
+				CodeOwner:      teamName == req.teamName && slices.Contains(owners, c.UserID),
+				MatchedLabels:  matchedLabels(c.Skills, req.labels),
+				Seniority:      c.Seniority,
+				OpenReviews:    c.OpenReviews,
+				MaxOpenReviews: c.MaxOpenReviews,
+				AvailableIn:    c.AvailableIn,
+			})
+		}
+	}
+
+	for _, a := range plan.picked {
+		r := storage.ReviewerAssignment{
+			UserID:        a.candidate.UserID,
+			Source:        a.source,
+			TeamName:      teamOf[a.candidate.ID],
+			MatchedLabels: a.matched,
+			Strategy:      a.strategy,
+		}
+		// у явно указанных сида нет — как и в сохранённом PR
+		if a.strategy != "" {
+			seed := a.seed
+			r.SelectionSeed = &seed
+		}
+		preview.Reviewers = append(preview.Reviewers, r)
+	}
+
+	return preview, nil
+}
+
+// memberState — участник команды с флагом активности
+type memberState struct {
+	id     int64
+	userID string
+	active bool
+}
+
+// teamMemberStates возвращает всех участников команды, включая неактивных
+func teamMemberStates(q querier, teamID int64) ([]memberState, error) {
+	rows, err := q.Query(`
+        SELECT id, user_id, is_active
+        FROM users
+        WHERE team_id = ?
+        ORDER BY id`, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []memberState
+	for rows.Next() {
+		var m memberState
+		var activeInt int
+		if err := rows.Scan(&m.id, &m.userID, &activeInt); err != nil {
+			return nil, err
+		}
+		m.active = activeInt == 1
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+func hasID(set map[int64]struct{}, id int64) bool {
+	_, ok := set[id]
+	return ok
+}
@@ -0,0 +1,210 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"pr-service/internal/selector"
+	"pr-service/internal/storage"
+	"sort"
+)
+
+// RebalanceTeam переносит ревью открытых PR с загруженных участников команды на недогруженных,
+// пока разница открытых ревью между самым и наименее загруженным не станет не больше tolerance
+// (или пока переносить больше нечего). Получатель выбирается как в ReassignReviewer:
+// не автор, не назначенный, не исключённый, не упёршийся в лимит, с учётом политики senior;
+// среди одинаково загруженных — стратегией команды.
+// dryRun — посчитать план и откатить транзакцию; план совпадёт с настоящим прогоном, если до него ничего не изменится.
+func (s *Storage) RebalanceTeam(teamName string, tolerance int, dryRun bool) (storage.RebalanceResult, error) {
+	const op = "storage.sqlite.RebalanceTeam"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.RebalanceResult{}, fmt.Errorf("%s: begin tx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var teamID int64
+	err = tx.QueryRow(`SELECT id FROM teams WHERE name = ?`, teamName).Scan(&teamID)
+	if err == sql.ErrNoRows {
+		return storage.RebalanceResult{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return storage.RebalanceResult{}, fmt.Errorf("%s: select team: %w", op, err)
+	}
+
+	settings, err := teamSettings(tx, teamID)
+	if err != nil {
+		return storage.RebalanceResult{}, fmt.Errorf("%s: team settings: %w", op, err)
+	}
+	sel := s.selectors.ForTeam(teamName, settings.Strategy)
+	strategy := s.selectors.NameForTeam(teamName, settings.Strategy)
+	source := s.source
+	if dryRun {
+		// пробный прогон не должен сдвигать ни источник сидов, ни очередь round_robin
+		sel = selector.Detached(sel)
+		source = source.Clone()
+	}
+
+	// нагрузка участников — те же счётчики открытых ревью, что используются при выборе
+	members, err := activeCandidates(tx, teamID)
+	if err != nil {
+		return storage.RebalanceResult{}, fmt.Errorf("%s: query members: %w", op, err)
+	}
+
+	res := storage.RebalanceResult{
+		TeamName:     teamName,
+		Tolerance:    tolerance,
+		DryRun:       dryRun,
+		SpreadBefore: loadSpread(members),
+		Moves:        []storage.RebalanceMove{},
+	}
+
+	// moveOne переносит одно ревью; false — подходящего переноса нет.
+	// Получатель всегда загружен минимум на 2 меньше донора, поэтому перекос строго уменьшается и цикл конечен.
+	moveOne := func() (bool, error) {
+		donors := make([]selector.Candidate, len(members))
+		copy(donors, members)
+		sort.SliceStable(donors, func(i, j int) bool { return donors[i].OpenReviews > donors[j].OpenReviews })
+
+		for _, donor := range donors {
+			prs, err := openReviewsOf(tx, donor.ID)
+			if err != nil {
+				return false, fmt.Errorf("query reviews of %s: %w", donor.UserID, err)
+			}
+
+			for _, pr := range prs {
+				unavailable, err := unavailableForPR(tx, pr.id, pr.authorID)
+				if err != nil {
+					return false, fmt.Errorf("query assigned reviewers: %w", err)
+				}
+
+				free, _ := splitByCapacity(without(members, unavailable))
+				receivers := make([]selector.Candidate, 0, len(free))
+				for _, c := range free {
+					if c.OpenReviews <= donor.OpenReviews-2 {
+						receivers = append(receivers, c)
+					}
+				}
+
+				wantSenior, err := seniorWanted(tx, pr.authorTeamID, pr.id, donor.ID)
+				if err != nil {
+					return false, fmt.Errorf("senior policy: %w", err)
+				}
+				if wantSenior && donor.Seniority == storage.SenioritySenior {
+					receivers = seniors(receivers)
+				}
+				if len(receivers) == 0 {
+					continue
+				}
+				receivers, err = withAuthorHistory(tx, receivers, pr.authorID)
+				if err != nil {
+					return false, fmt.Errorf("query author history: %w", err)
+				}
+
+				labels, err := prLabels(tx, pr.extID)
+				if err != nil {
+					return false, fmt.Errorf("query labels: %w", err)
+				}
+
+				// сначала наименее загруженные, среди них — как при переназначении
+				replacement := replacementRank(labels, wantSenior)
+				tiers := rankTiers(receivers, func(c selector.Candidate) []int {
+					return append([]int{c.OpenReviews}, replacement(c)...)
+				})
+				seed := source.Draw()
+				chosen := selector.SelectTiered(sel, selector.NewRand(seed), teamName, tiers, 1)[0]
+
+				if err := updateAssignment(tx, pr.id, donor.ID, assignment{
+					candidate: chosen,
+					source:    sourceFor(teamID, pr.authorTeamID),
+					teamID:    teamID,
+					matched:   matchedLabels(chosen.Skills, labels),
+					strategy:  strategy,
+					seed:      seed,
+				}, storage.EventReasonRebalance, storage.EventActorSystem); err != nil {
+					return false, fmt.Errorf("update reviewer in pr: %w", err)
+				}
+
+				addOpenReviews(members, donor.ID, -1)
+				addOpenReviews(members, chosen.ID, 1)
+				res.Moves = append(res.Moves, storage.RebalanceMove{
+					PullRequestID: pr.extID,
+					FromUserID:    donor.UserID,
+					ToUserID:      chosen.UserID,
+				})
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	for loadSpread(members) > tolerance {
+		moved, err := moveOne()
+		if err != nil {
+			return storage.RebalanceResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if !moved {
+			break
+		}
+	}
+	res.SpreadAfter = loadSpread(members)
+
+	if dryRun {
+		return res, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.RebalanceResult{}, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// openReview — открытый PR, на котором пользователь ревьювер
+type openReview struct {
+	id           int64
+	extID        string
+	authorID     int64
+	authorTeamID int64
+}
+
+// openReviewsOf возвращает открытые PR ревьювера, новые первыми:
+// их переносить проще всего — ревью, скорее всего, ещё не начато
+func openReviewsOf(q querier, reviewerID int64) ([]openReview, error) {
+	rows, err := q.Query(`
+        SELECT pr.id, pr.pull_request_id, pr.author_id, au.team_id
+        FROM pr_reviewers r
+        JOIN pull_requests pr ON r.pr_id = pr.id
+        JOIN users au ON pr.author_id = au.id
+        WHERE r.reviewer_id = ? AND pr.status = 'OPEN'
+        ORDER BY pr.created_at DESC, pr.id DESC`, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []openReview
+	for rows.Next() {
+		var pr openReview
+		if err := rows.Scan(&pr.id, &pr.extID, &pr.authorID, &pr.authorTeamID); err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+
+	return prs, rows.Err()
+}
+
+// loadSpread — разница открытых ревью между самым и наименее загруженным кандидатом
+func loadSpread(candidates []selector.Candidate) int {
+	if len(candidates) == 0 {
+		return 0
+	}
+	lo, hi := candidates[0].OpenReviews, candidates[0].OpenReviews
+	for _, c := range candidates[1:] {
+		lo = min(lo, c.OpenReviews)
+		hi = max(hi, c.OpenReviews)
+	}
+	return hi - lo
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"pr-service/internal/lib/workhours"
+	"pr-service/internal/storage"
+	"time"
+)
+
+// DueReminders возвращает, кому пора напомнить о ревью: назначения на открытые PR без итога ревью,
+// висящие дольше threshold, если по этому PR ревьюверу не напоминали последние minInterval.
+// Неактивные, отсутствующие и те, у кого сейчас тихие часы, пропускаются — им напомнят в следующий проход.
+func (s *Storage) DueReminders(now time.Time, threshold, minInterval time.Duration) ([]storage.Reminder, error) {
+	const op = "storage.sqlite.DueReminders"
+
+	rows, err := s.db.Query(`
+        SELECT pr.pull_request_id, pr.name, au.user_id, u.user_id, u.username,
+               u.timezone, u.quiet_start, u.quiet_end, r.assigned_at, rm.sent_at
+        FROM pr_reviewers r
+        JOIN pull_requests pr ON r.pr_id = pr.id
+        JOIN users au ON pr.author_id = au.id
+        JOIN users u ON r.reviewer_id = u.id
+        LEFT JOIN pr_reminders rm ON rm.pr_id = r.pr_id AND rm.reviewer_id = r.reviewer_id
+        WHERE pr.status = 'OPEN' AND r.verdict IS NULL AND u.is_active = 1
+          AND r.assigned_at <= ?
+          AND (rm.sent_at IS NULL OR rm.sent_at <= ?)
+          AND NOT EXISTS (SELECT 1 FROM user_absences a
+                          WHERE a.user_id = u.id AND a.starts_at <= ? AND a.ends_at > ?)
+        ORDER BY r.assigned_at, pr.id, u.id`,
+		sqlTime(now.Add(-threshold)), sqlTime(now.Add(-minInterval)), sqlTime(now), sqlTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	reminders := []storage.Reminder{}
+	for rows.Next() {
+		var r storage.Reminder
+		var timezone, quietStart, quietEnd string
+		var sentAt sql.NullTime
+		if err := rows.Scan(&r.PullRequestID, &r.PullRequestName, &r.AuthorID, &r.UserID, &r.Username,
+			&timezone, &quietStart, &quietEnd, &r.AssignedAt, &sentAt); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+
+		quiet, err := workhours.ParseQuiet(timezone, quietStart, quietEnd)
+		if err != nil {
+			return nil, fmt.Errorf("%s: user %s: %w", op, r.UserID, err)
+		}
+		if quiet.Contains(now) {
+			continue
+		}
+
+		if sentAt.Valid {
+			t := sentAt.Time
+			r.LastRemindedAt = &t
+		}
+		reminders = append(reminders, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows err: %w", op, err)
+	}
+
+	return reminders, nil
+}
+
+// MarkReminderSent запоминает, что ревьюверу напомнили о PR; от этого момента отсчитывается minInterval
+func (s *Storage) MarkReminderSent(prID, userID string, at time.Time) error {
+	const op = "storage.sqlite.MarkReminderSent"
+
+	_, err := s.db.Exec(`
+        INSERT INTO pr_reminders(pr_id, reviewer_id, sent_at)
+        SELECT pr.id, u.id, ?
+        FROM pull_requests pr, users u
+        WHERE pr.pull_request_id = ? AND u.user_id = ?
+        ON CONFLICT(pr_id, reviewer_id) DO UPDATE SET sent_at = excluded.sent_at`,
+		sqlTime(at), prID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
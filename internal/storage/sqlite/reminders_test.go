@@ -0,0 +1,175 @@
+package sqlite
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"pr-service/internal/storage"
+)
+
+// dueUserIDs — кому DueReminders напомнил бы в момент now
+func dueUserIDs(t *testing.T, s *Storage, now time.Time, threshold, minInterval time.Duration) []string {
+	t.Helper()
+
+	due, err := s.DueReminders(now, threshold, minInterval)
+	if err != nil {
+		t.Fatalf("due reminders: %v", err)
+	}
+	ids := make([]string, 0, len(due))
+	for _, r := range due {
+		ids = append(ids, r.UserID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestDueRemindersThreshold(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "first")
+	mustCreatePR(t, s, "pr-1", "author", "first")
+
+	now := time.Now()
+	if got := dueUserIDs(t, s, now.Add(30*time.Minute), time.Hour, time.Hour); len(got) != 0 {
+		t.Errorf("due before threshold = %v, want none", got)
+	}
+	if got := dueUserIDs(t, s, now.Add(2*time.Hour), time.Hour, time.Hour); !slices.Equal(got, []string{"first"}) {
+		t.Errorf("due after threshold = %v, want [first]", got)
+	}
+
+	// с итогом ревью напоминать не о чем
+	if _, err := s.SubmitReview("pr-1", "first", storage.VerdictApproved); err != nil {
+		t.Fatalf("submit review: %v", err)
+	}
+	if got := dueUserIDs(t, s, now.Add(2*time.Hour), time.Hour, time.Hour); len(got) != 0 {
+		t.Errorf("due after review = %v, want none", got)
+	}
+}
+
+func TestDueRemindersMinInterval(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "first", "second")
+	mustCreatePR(t, s, "pr-1", "author", "first")
+	mustCreatePR(t, s, "pr-2", "author", "second")
+
+	sentAt := time.Now().Add(2 * time.Hour)
+	if err := s.MarkReminderSent("pr-1", "first", sentAt); err != nil {
+		t.Fatalf("mark reminder sent: %v", err)
+	}
+
+	// интервал считается по паре (PR, ревьювер): second по pr-2 ещё не напоминали
+	if got := dueUserIDs(t, s, sentAt.Add(30*time.Minute), time.Hour, time.Hour); !slices.Equal(got, []string{"second"}) {
+		t.Errorf("due within interval = %v, want [second]", got)
+	}
+
+	due, err := s.DueReminders(sentAt.Add(2*time.Hour), time.Hour, time.Hour)
+	if err != nil {
+		t.Fatalf("due reminders: %v", err)
+	}
+	if len(due) != 2 {
+		t.Fatalf("due after interval = %v, want both", due)
+	}
+	for _, r := range due {
+		switch r.UserID {
+		case "first":
+			if r.LastRemindedAt == nil || !r.LastRemindedAt.Equal(sentAt.UTC().Truncate(time.Second)) {
+				t.Errorf("first last reminded = %v, want %v", r.LastRemindedAt, sentAt)
+			}
+		case "second":
+			if r.LastRemindedAt != nil {
+				t.Errorf("second last reminded = %v, want nil", r.LastRemindedAt)
+			}
+		}
+	}
+}
+
+func TestDueRemindersQuietHours(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "night", "day")
+	mustCreatePR(t, s, "pr-1", "author", "night")
+	mustCreatePR(t, s, "pr-2", "author", "day")
+
+	setQuiet := func(userID, start, end string) {
+		t.Helper()
+		tz := "UTC"
+		if _, err := s.UpdateUser(userID, storage.UserUpdate{Timezone: &tz, QuietStart: &start, QuietEnd: &end}); err != nil {
+			t.Fatalf("set quiet hours %s: %v", userID, err)
+		}
+	}
+	setQuiet("night", "22:00", "07:00") // окно через полночь
+	setQuiet("day", "12:00", "14:00")
+
+	day := time.Now().UTC().Truncate(24 * time.Hour).Add(48 * time.Hour)
+	tests := []struct {
+		name string
+		now  time.Time
+		want []string
+	}{
+		{name: "before midnight", now: day.Add(23 * time.Hour), want: []string{"day"}},
+		{name: "after midnight", now: day.Add(3 * time.Hour), want: []string{"day"}},
+		{name: "quiet end is exclusive", now: day.Add(7 * time.Hour), want: []string{"day", "night"}},
+		{name: "daytime window", now: day.Add(13 * time.Hour), want: []string{"night"}},
+		{name: "nobody quiet", now: day.Add(18 * time.Hour), want: []string{"day", "night"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dueUserIDs(t, s, tt.now, time.Hour, time.Hour); !slices.Equal(got, tt.want) {
+				t.Errorf("due = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDueRemindersSkipsAbsentAndInactive(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "absent", "inactive", "present")
+	mustCreatePR(t, s, "pr-1", "author", "absent")
+	mustCreatePR(t, s, "pr-2", "author", "inactive")
+	mustCreatePR(t, s, "pr-3", "author", "present")
+
+	// отсутствие в будущем: ревью пока остаются за ним
+	now := time.Now()
+	if _, err := s.SetUserAbsence("absent", now.Add(time.Hour), now.Add(5*time.Hour)); err != nil {
+		t.Fatalf("set absence: %v", err)
+	}
+	inactive := false
+	if _, err := s.UpdateUser("inactive", storage.UserUpdate{IsActive: &inactive}); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+
+	if got := dueUserIDs(t, s, now.Add(2*time.Hour), time.Hour, time.Hour); !slices.Equal(got, []string{"present"}) {
+		t.Errorf("due during absence = %v, want [present]", got)
+	}
+	if got := dueUserIDs(t, s, now.Add(6*time.Hour), time.Hour, time.Hour); !slices.Equal(got, []string{"absent", "present"}) {
+		t.Errorf("due after absence = %v, want [absent present]", got)
+	}
+}
+
+func TestDueRemindersAfterReopen(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "first")
+	mustCreatePR(t, s, "pr-1", "author", "first")
+
+	now := time.Now()
+	if err := s.MarkReminderSent("pr-1", "first", now); err != nil {
+		t.Fatalf("mark reminder sent: %v", err)
+	}
+	if _, err := s.ClosePullRequest("pr-1"); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := s.ReopenPullRequest("pr-1"); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+
+	// после reopen отсчёт начинается заново: напоминание до закрытия не в счёт
+	if got := dueUserIDs(t, s, now.Add(30*time.Minute), time.Hour, 24*time.Hour); len(got) != 0 {
+		t.Errorf("due before threshold = %v, want none", got)
+	}
+	due, err := s.DueReminders(now.Add(2*time.Hour), time.Hour, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("due reminders: %v", err)
+	}
+	if len(due) != 1 || due[0].LastRemindedAt != nil {
+		t.Errorf("due after reopen = %+v, want first without previous reminder", due)
+	}
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"pr-service/internal/storage"
+)
+
+// SubmitReview записывает итог ревью назначенного ревьювера; повторный итог заменяет прежний.
+// Итог можно оставить только у открытого PR. Первый итог отмечает first_action_at,
+// первый APPROVED или CHANGES_REQUESTED — completed_at; COMMENTED ревью не завершает.
+func (s *Storage) SubmitReview(prID, userID, verdict string) (storage.PullRequest, error) {
+	const op = "storage.sqlite.SubmitReview"
+
+	switch verdict {
+	case storage.VerdictApproved, storage.VerdictChangesRequested, storage.VerdictCommented:
+	default:
+		return storage.PullRequest{}, fmt.Errorf("%w: %q", storage.ErrInvalidVerdict, verdict)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	prIntID, status, err := prStatus(tx, prID)
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	switch status {
+	case "MERGED":
+		return storage.PullRequest{}, storage.ErrPRMerged
+	case "CLOSED":
+		return storage.PullRequest{}, storage.ErrPRClosed
+	case "DRAFT":
+		return storage.PullRequest{}, storage.ErrPRDraft
+	}
+
+	var reviewerID int64
+	err = tx.QueryRow(`SELECT id FROM users WHERE user_id = ?`, userID).Scan(&reviewerID)
+	if err == sql.ErrNoRows {
+		return storage.PullRequest{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.Exec(`
+        UPDATE pr_reviewers
+        SET verdict = ?, verdict_at = CURRENT_TIMESTAMP,
+            first_action_at = COALESCE(first_action_at, CURRENT_TIMESTAMP),
+            completed_at = CASE
+                WHEN completed_at IS NULL AND ? IN ('APPROVED', 'CHANGES_REQUESTED') THEN CURRENT_TIMESTAMP
+                ELSE completed_at
+            END
+        WHERE pr_id = ? AND reviewer_id = ?`, verdict, verdict, prIntID, reviewerID)
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return storage.PullRequest{}, storage.ErrNotAssigned
+	}
+	if err := recordEvent(tx, prIntID, prEvent{
+		typ:    storage.EventReviewSubmitted,
+		userID: reviewerID,
+		reason: verdict,
+		actor:  userID,
+	}); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.getPullRequestByExternalID(prID)
+}
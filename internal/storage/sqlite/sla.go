@@ -0,0 +1,207 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"pr-service/internal/lib/workhours"
+	"pr-service/internal/storage"
+	"sort"
+	"time"
+)
+
+// ProcessReviewSLA — проход планировщика по SLA ревью: назначения на открытые PR без итога ревью,
+// у которых рабочее время ревьювера с момента назначения превысило SLA команды автора, отмечаются как нарушение.
+// При политике REASSIGN ревьювер заменяется так же, как в ReassignReviewer; если замены нет, нарушение только отмечается.
+// Каждое назначение нарушает SLA не больше одного раза; после замены SLA для нового ревьювера считается заново.
+func (s *Storage) ProcessReviewSLA(now time.Time) (storage.SLARunResult, error) {
+	const op = "storage.sqlite.ProcessReviewSLA"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.SLARunResult{}, fmt.Errorf("%s: begin tx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	type overdue struct {
+		prID, reviewerID, teamID     int64
+		prExtID, userID, teamName    string
+		timezone, workStart, workEnd string
+		assignedAt                   time.Time
+		sla                          time.Duration
+		action                       string
+	}
+
+	// рабочее время не больше календарного, поэтому заведомо не просроченные отсекаются ещё в запросе
+	rows, err := tx.Query(`
+        SELECT pr.id, pr.pull_request_id, r.reviewer_id, u.user_id, u.timezone, u.work_start, u.work_end,
+               r.assigned_at, ts.team_id, t.name, ts.review_sla_seconds, ts.review_sla_action
+        FROM pr_reviewers r
+        JOIN pull_requests pr ON r.pr_id = pr.id
+        JOIN users u ON r.reviewer_id = u.id
+        JOIN users au ON pr.author_id = au.id
+        JOIN team_settings ts ON ts.team_id = au.team_id
+        JOIN teams t ON ts.team_id = t.id
+        WHERE pr.status = 'OPEN' AND r.verdict IS NULL AND ts.review_sla_seconds > 0
+          AND r.assigned_at <= datetime(?, '-' || ts.review_sla_seconds || ' seconds')
+          AND NOT EXISTS (SELECT 1 FROM sla_breaches b
+                          WHERE b.pr_id = r.pr_id AND b.reviewer_id = r.reviewer_id AND b.assigned_at = r.assigned_at)
+        ORDER BY r.assigned_at, pr.id, r.reviewer_id`, sqlTime(now))
+	if err != nil {
+		return storage.SLARunResult{}, fmt.Errorf("%s: query assignments: %w", op, err)
+	}
+	defer rows.Close()
+
+	var candidates []overdue
+	for rows.Next() {
+		var o overdue
+		var slaSeconds int64
+		if err := rows.Scan(&o.prID, &o.prExtID, &o.reviewerID, &o.userID, &o.timezone, &o.workStart, &o.workEnd,
+			&o.assignedAt, &o.teamID, &o.teamName, &slaSeconds, &o.action); err != nil {
+			return storage.SLARunResult{}, fmt.Errorf("%s: scan assignment: %w", op, err)
+		}
+		o.sla = time.Duration(slaSeconds) * time.Second
+		candidates = append(candidates, o)
+	}
+	if err := rows.Err(); err != nil {
+		return storage.SLARunResult{}, fmt.Errorf("%s: assignments rows err: %w", op, err)
+	}
+	rows.Close()
+
+	res := storage.SLARunResult{Breaches: []storage.SLABreach{}}
+	detectedAt := now.UTC().Truncate(time.Second)
+
+	for _, o := range candidates {
+		schedule, err := workhours.Parse(o.timezone, o.workStart, o.workEnd)
+		if err != nil {
+			return storage.SLARunResult{}, fmt.Errorf("%s: user %s: %w", op, o.userID, err)
+		}
+		if schedule.Between(o.assignedAt, now) < o.sla {
+			continue
+		}
+
+		// assigned_at берётся из самой строки назначения, чтобы совпасть с ней и в проверке выше
+		ins, err := tx.Exec(`
+            INSERT INTO sla_breaches(pr_id, reviewer_id, team_id, assigned_at, detected_at, outcome)
+            SELECT pr_id, reviewer_id, ?, assigned_at, ?, ?
+            FROM pr_reviewers
+            WHERE pr_id = ? AND reviewer_id = ?`,
+			o.teamID, sqlTime(detectedAt), storage.SLABreachFlagged, o.prID, o.reviewerID)
+		if err != nil {
+			return storage.SLARunResult{}, fmt.Errorf("%s: insert breach: %w", op, err)
+		}
+		breachID, _ := ins.LastInsertId()
+
+		if err := recordEvent(tx, o.prID, prEvent{
+			typ:    storage.EventSLABreached,
+			userID: o.reviewerID,
+			reason: o.action,
+			actor:  storage.EventActorSystem,
+		}); err != nil {
+			return storage.SLARunResult{}, fmt.Errorf("%s: record breach: %w", op, err)
+		}
+
+		breach := storage.SLABreach{
+			PullRequestID: o.prExtID,
+			UserID:        o.userID,
+			TeamName:      o.teamName,
+			AssignedAt:    o.assignedAt,
+			DetectedAt:    detectedAt,
+			Outcome:       storage.SLABreachFlagged,
+		}
+
+		if o.action == storage.SLAActionReassign {
+			replacedBy, err := s.replaceReviewer(tx, o.prExtID, o.userID, storage.EventReasonSLABreach, storage.EventActorSystem)
+			switch {
+			case errors.Is(err, storage.ErrNoCandidate), errors.Is(err, storage.ErrNoSeniorCandidate):
+				// заменить некем — нарушение остаётся отмеченным
+			case err != nil:
+				return storage.SLARunResult{}, fmt.Errorf("%s: reassign %s on %s: %w", op, o.userID, o.prExtID, err)
+			default:
+				if _, err := tx.Exec(`
+                    UPDATE sla_breaches
+                    SET outcome = ?, replaced_by = (SELECT id FROM users WHERE user_id = ?)
+                    WHERE id = ?`, storage.SLABreachReassigned, replacedBy, breachID); err != nil {
+					return storage.SLARunResult{}, fmt.Errorf("%s: update breach: %w", op, err)
+				}
+				breach.Outcome = storage.SLABreachReassigned
+				breach.ReplacedBy = replacedBy
+			}
+		}
+
+		res.Breaches = append(res.Breaches, breach)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.SLARunResult{}, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// GetSLAStats возвращает нарушения SLA ревью по командам авторов; teamName пусто — по всем командам
+func (s *Storage) GetSLAStats(teamName string) (storage.SLAStats, error) {
+	const op = "storage.sqlite.GetSLAStats"
+
+	if teamName != "" {
+		var tmp int
+		err := s.db.QueryRow(`SELECT 1 FROM teams WHERE name = ?`, teamName).Scan(&tmp)
+		if err == sql.ErrNoRows {
+			return storage.SLAStats{}, storage.ErrNotFound
+		}
+		if err != nil {
+			return storage.SLAStats{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	rows, err := s.db.Query(`
+        SELECT pr.pull_request_id, u.user_id, t.name, b.assigned_at, b.detected_at, b.outcome, COALESCE(rb.user_id, '')
+        FROM sla_breaches b
+        JOIN pull_requests pr ON b.pr_id = pr.id
+        JOIN users u ON b.reviewer_id = u.id
+        JOIN teams t ON b.team_id = t.id
+        LEFT JOIN users rb ON b.replaced_by = rb.id
+        WHERE ? = '' OR t.name = ?
+        ORDER BY b.detected_at DESC, b.id DESC`, teamName, teamName)
+	if err != nil {
+		return storage.SLAStats{}, fmt.Errorf("%s: query breaches: %w", op, err)
+	}
+	defer rows.Close()
+
+	stats := storage.SLAStats{
+		TeamName:   teamName,
+		ByReviewer: []storage.SLAReviewerStat{},
+		Breaches:   []storage.SLABreach{},
+	}
+	byReviewer := make(map[string]int)
+	for rows.Next() {
+		var b storage.SLABreach
+		if err := rows.Scan(&b.PullRequestID, &b.UserID, &b.TeamName, &b.AssignedAt, &b.DetectedAt, &b.Outcome, &b.ReplacedBy); err != nil {
+			return storage.SLAStats{}, fmt.Errorf("%s: scan breach: %w", op, err)
+		}
+		stats.Breaches = append(stats.Breaches, b)
+
+		stats.Total++
+		if b.Outcome == storage.SLABreachReassigned {
+			stats.Reassigned++
+		} else {
+			stats.Flagged++
+		}
+		byReviewer[b.UserID]++
+	}
+	if err := rows.Err(); err != nil {
+		return storage.SLAStats{}, fmt.Errorf("%s: breaches rows err: %w", op, err)
+	}
+
+	for userID, n := range byReviewer {
+		stats.ByReviewer = append(stats.ByReviewer, storage.SLAReviewerStat{UserID: userID, Breaches: n})
+	}
+	sort.Slice(stats.ByReviewer, func(i, j int) bool {
+		if stats.ByReviewer[i].Breaches != stats.ByReviewer[j].Breaches {
+			return stats.ByReviewer[i].Breaches > stats.ByReviewer[j].Breaches
+		}
+		return stats.ByReviewer[i].UserID < stats.ByReviewer[j].UserID
+	})
+
+	return stats, nil
+}
@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"pr-service/internal/storage"
+)
+
+// mustSetSLA включает SLA ревью для команды
+func mustSetSLA(t *testing.T, s *Storage, teamName string, sla time.Duration, action string) {
+	t.Helper()
+
+	if _, err := s.UpdateTeamSettings(teamName, storage.TeamSettingsUpdate{ReviewSLA: &sla, ReviewSLAAction: &action}); err != nil {
+		t.Fatalf("set sla %s: %v", teamName, err)
+	}
+}
+
+func TestProcessReviewSLAReassigns(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "first", "second")
+	mustCreatePR(t, s, "pr-1", "author", "first")
+	mustSetSLA(t, s, "backend", time.Hour, storage.SLAActionReassign)
+
+	// сама настройка SLA ревью не трогает — это работа планировщика
+	if got := mustGetPR(t, s, "pr-1").AssignedReviewers; !slices.Equal(got, []string{"first"}) {
+		t.Fatalf("reviewers after settings = %v, want [first]", got)
+	}
+
+	res, err := s.ProcessReviewSLA(time.Now())
+	if err != nil {
+		t.Fatalf("process sla: %v", err)
+	}
+	if len(res.Breaches) != 0 {
+		t.Fatalf("breaches before deadline = %v, want none", res.Breaches)
+	}
+
+	res, err = s.ProcessReviewSLA(time.Now().Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("process sla: %v", err)
+	}
+	if len(res.Breaches) != 1 {
+		t.Fatalf("breaches = %v, want one", res.Breaches)
+	}
+	b := res.Breaches[0]
+	if b.PullRequestID != "pr-1" || b.UserID != "first" || b.Outcome != storage.SLABreachReassigned || b.ReplacedBy != "second" {
+		t.Errorf("breach = %+v, want first reassigned to second on pr-1", b)
+	}
+	if got := mustGetPR(t, s, "pr-1").AssignedReviewers; !slices.Equal(got, []string{"second"}) {
+		t.Errorf("reviewers = %v, want [second]", got)
+	}
+
+	stats, err := s.GetSLAStats("backend")
+	if err != nil {
+		t.Fatalf("sla stats: %v", err)
+	}
+	if stats.Total != 1 || stats.Reassigned != 1 || stats.Flagged != 0 {
+		t.Errorf("stats = %+v, want one reassigned breach", stats)
+	}
+}
+
+func TestProcessReviewSLAFlagsOnce(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "first", "second")
+	mustCreatePR(t, s, "pr-1", "author", "first")
+	mustSetSLA(t, s, "backend", time.Hour, storage.SLAActionFlag)
+
+	later := time.Now().Add(2 * time.Hour)
+	for i := 0; i < 2; i++ {
+		if _, err := s.ProcessReviewSLA(later.Add(time.Duration(i) * time.Minute)); err != nil {
+			t.Fatalf("process sla: %v", err)
+		}
+	}
+
+	if got := mustGetPR(t, s, "pr-1").AssignedReviewers; !slices.Equal(got, []string{"first"}) {
+		t.Errorf("reviewers = %v, want [first] kept on FLAG", got)
+	}
+	stats, err := s.GetSLAStats("backend")
+	if err != nil {
+		t.Fatalf("sla stats: %v", err)
+	}
+	if stats.Total != 1 || stats.Flagged != 1 {
+		t.Errorf("stats = %+v, want exactly one flagged breach", stats)
+	}
+}
+
+func TestReopenRestartsSLAClock(t *testing.T) {
+	s := newTestStorage(t)
+	mustCreateTeam(t, s, "backend", 1, "author", "first", "second")
+	mustCreatePR(t, s, "pr-1", "author", "first")
+	mustSetSLA(t, s, "backend", time.Hour, storage.SLAActionReassign)
+
+	if _, err := s.ClosePullRequest("pr-1"); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	// PR пролежал закрытым дольше SLA
+	if _, err := s.db.Exec(`UPDATE pr_reviewers SET assigned_at = ?`, sqlTime(time.Now().Add(-3*time.Hour))); err != nil {
+		t.Fatalf("move assigned_at: %v", err)
+	}
+	if _, err := s.ReopenPullRequest("pr-1"); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+
+	res, err := s.ProcessReviewSLA(time.Now().Add(30 * time.Minute))
+	if err != nil {
+		t.Fatalf("process sla: %v", err)
+	}
+	if len(res.Breaches) != 0 {
+		t.Errorf("breaches after reopen = %v, want none", res.Breaches)
+	}
+	if got := mustGetPR(t, s, "pr-1").AssignedReviewers; !slices.Equal(got, []string{"first"}) {
+		t.Errorf("reviewers = %v, want [first]", got)
+	}
+}
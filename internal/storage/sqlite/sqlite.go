@@ -1,18 +1,31 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
-	"math/rand/v2"
+	"pr-service/internal/lib/workhours"
+	"pr-service/internal/selector"
 	"pr-service/internal/storage"
+	"strings"
 	"time"
 )
 
 type Storage struct {
-	db *sql.DB
+	db        *sql.DB
+	selectors *selector.Registry
+	source    *selector.Source
 }
 
-func New(storagePath string) (*Storage, error) {
+// querier — общее между *sql.DB и *sql.Tx
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func New(storagePath string, selectors *selector.Registry, source *selector.Source) (*Storage, error) {
 	const op = "storage.sqlite.New"
 
 	db, err := sql.Open("sqlite3", storagePath)
@@ -40,6 +53,13 @@ CREATE TABLE IF NOT EXISTS users (
     username    TEXT NOT NULL,
     team_id     INTEGER NOT NULL,
     is_active   INTEGER NOT NULL DEFAULT 1,
+    max_open_reviews INTEGER NOT NULL DEFAULT 0, -- 0 — без ограничения
+    seniority   TEXT NOT NULL DEFAULT 'middle' CHECK (seniority IN ('junior', 'middle', 'senior')),
+    timezone    TEXT NOT NULL DEFAULT '',  -- IANA; '' — UTC
+    work_start  TEXT NOT NULL DEFAULT '',  -- HH:MM; '' — расписание не задано
+    work_end    TEXT NOT NULL DEFAULT '',
+    quiet_start TEXT NOT NULL DEFAULT '',  -- HH:MM; '' — тихих часов нет
+    quiet_end   TEXT NOT NULL DEFAULT '',
     FOREIGN KEY (team_id) REFERENCES teams(id)
 );
 
@@ -49,21 +69,177 @@ CREATE TABLE IF NOT EXISTS pull_requests (
     pull_request_id  TEXT NOT NULL UNIQUE, -- внешний id из API (pr-1001)
     name             TEXT NOT NULL,
     author_id        INTEGER NOT NULL,
-    status           TEXT NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    status           TEXT NOT NULL CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED')),
     created_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     merged_at        DATETIME NULL,
+    pending_reviewers INTEGER NOT NULL DEFAULT 0, -- незаполненные места ревьюверов
+    ready_at         DATETIME NULL, -- когда PR стал OPEN; NULL — ещё черновик
+    closed_at        DATETIME NULL,
     FOREIGN KEY (author_id) REFERENCES users(id)
 );
 
 -- pr_reviewers (многие ко многим: PR <-> ревьюверы)
 CREATE TABLE IF NOT EXISTS pr_reviewers (
+    pr_id          INTEGER NOT NULL,
+    reviewer_id    INTEGER NOT NULL,
+    source         TEXT NOT NULL DEFAULT 'team', -- team, codeowner, fallback, requested
+    source_team_id INTEGER NULL,                 -- команда ревьювера на момент назначения
+    matched_labels TEXT NOT NULL DEFAULT '[]',   -- JSON-массив меток PR, закрытых навыками ревьювера
+    strategy       TEXT NOT NULL DEFAULT '',     -- стратегия, которой выбран ревьювер
+    selection_seed INTEGER NULL,                 -- сид выбора (selector.Source.Draw), по нему выбор можно повторить
+    verdict        TEXT NULL CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')), -- последний итог ревью
+    verdict_at     DATETIME NULL,
+    assigned_at    DATETIME NULL,                -- когда назначен нынешний ревьювер; от него считается SLA
+    first_action_at DATETIME NULL,               -- первый итог ревью любого вида
+    completed_at   DATETIME NULL,                -- первый APPROVED или CHANGES_REQUESTED
+    PRIMARY KEY (pr_id, reviewer_id),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (reviewer_id) REFERENCES users(id)
+);
+
+-- team_settings (настройки автоназначения; нет строки — значения по умолчанию)
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_id          INTEGER PRIMARY KEY,
+    reviewer_count   INTEGER NOT NULL DEFAULT 2 CHECK (reviewer_count >= 1),
+    strategy         TEXT NOT NULL DEFAULT '',  -- '' — стратегия из конфига
+    allow_cross_team INTEGER NOT NULL DEFAULT 0,
+    fallback_teams   TEXT NOT NULL DEFAULT '[]', -- JSON-массив имён команд
+    require_senior   INTEGER NOT NULL DEFAULT 0, -- среди ревьюверов нужен хотя бы один senior
+    required_approvals INTEGER NOT NULL DEFAULT 0, -- условия merge (storage.MergePolicy)
+    block_on_changes_requested INTEGER NOT NULL DEFAULT 0,
+    require_senior_or_owner_approval INTEGER NOT NULL DEFAULT 0,
+    review_sla_seconds INTEGER NOT NULL DEFAULT 0 CHECK (review_sla_seconds >= 0), -- SLA ревью в рабочих секундах; 0 — нет
+    review_sla_action  TEXT NOT NULL DEFAULT 'FLAG' CHECK (review_sla_action IN ('FLAG', 'REASSIGN')),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+-- team_codeowners (правила CODEOWNERS команды в исходном виде)
+CREATE TABLE IF NOT EXISTS team_codeowners (
+    team_id     INTEGER PRIMARY KEY,
+    content     TEXT NOT NULL,
+    updated_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+-- pr_files (изменённые в PR пути)
+CREATE TABLE IF NOT EXISTS pr_files (
+    pr_id       INTEGER NOT NULL,
+    path        TEXT NOT NULL,
+    PRIMARY KEY (pr_id, path),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+-- user_skills (навыки пользователей)
+CREATE TABLE IF NOT EXISTS user_skills (
+    user_id     INTEGER NOT NULL,
+    skill       TEXT NOT NULL,
+    PRIMARY KEY (user_id, skill),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- pr_labels (метки PR)
+CREATE TABLE IF NOT EXISTS pr_labels (
+    pr_id       INTEGER NOT NULL,
+    label       TEXT NOT NULL,
+    PRIMARY KEY (pr_id, label),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+-- reviewer_exclusions (пары, которые не ревьюят PR друг друга; user_a < user_b)
+CREATE TABLE IF NOT EXISTS reviewer_exclusions (
+    user_a      INTEGER NOT NULL,
+    user_b      INTEGER NOT NULL,
+    reason      TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_a, user_b),
+    CHECK (user_a < user_b),
+    FOREIGN KEY (user_a) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_b) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- pr_excluded_reviewers (кого нельзя назначать на конкретный PR)
+CREATE TABLE IF NOT EXISTS pr_excluded_reviewers (
+    pr_id       INTEGER NOT NULL,
+    user_id     INTEGER NOT NULL,
+    PRIMARY KEY (pr_id, user_id),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- pr_requested_reviewers (явно указанные ревьюверы черновика; назначаются при переводе в OPEN)
+CREATE TABLE IF NOT EXISTS pr_requested_reviewers (
+    pr_id       INTEGER NOT NULL,
+    user_id     INTEGER NOT NULL,
+    PRIMARY KEY (pr_id, user_id),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- pr_declines (отказы ревьюверов; отказавшихся больше не назначают на этот PR)
+CREATE TABLE IF NOT EXISTS pr_declines (
+    pr_id       INTEGER NOT NULL,
+    user_id     INTEGER NOT NULL,
+    reason      TEXT NOT NULL CHECK (reason IN ('CONFLICT', 'NO_EXPERTISE', 'OVERLOADED')),
+    replaced_by INTEGER NULL,
+    declined_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (pr_id, user_id),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (replaced_by) REFERENCES users(id)
+);
+
+-- pr_events (история PR; строки только добавляются)
+CREATE TABLE IF NOT EXISTS pr_events (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    pr_id          INTEGER NOT NULL,
+    type           TEXT NOT NULL,              -- storage.Event*
+    user_id        INTEGER NULL,               -- ревьювер, которого касается событие
+    replacement_id INTEGER NULL,               -- reviewer_replaced: новый ревьювер
+    reason         TEXT NOT NULL DEFAULT '',
+    actor          TEXT NOT NULL DEFAULT '',   -- user_id инициатора, 'system' или '' — неизвестен
+    created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (replacement_id) REFERENCES users(id)
+);
+
+-- sla_breaches (назначения, по которым ревью не сделано в срок SLA команды автора)
+CREATE TABLE IF NOT EXISTS sla_breaches (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    pr_id       INTEGER NOT NULL,
+    reviewer_id INTEGER NOT NULL,
+    team_id     INTEGER NOT NULL,  -- команда автора, чей SLA нарушен
+    assigned_at DATETIME NOT NULL, -- вместе с pr_id и reviewer_id определяет назначение
+    detected_at DATETIME NOT NULL,
+    outcome     TEXT NOT NULL CHECK (outcome IN ('FLAGGED', 'REASSIGNED')),
+    replaced_by INTEGER NULL,
+    UNIQUE (pr_id, reviewer_id, assigned_at),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (reviewer_id) REFERENCES users(id),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    FOREIGN KEY (replaced_by) REFERENCES users(id)
+);
+
+-- pr_reminders (когда ревьюверу последний раз напоминали о PR)
+CREATE TABLE IF NOT EXISTS pr_reminders (
     pr_id       INTEGER NOT NULL,
     reviewer_id INTEGER NOT NULL,
+    sent_at     DATETIME NOT NULL,
     PRIMARY KEY (pr_id, reviewer_id),
     FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
     FOREIGN KEY (reviewer_id) REFERENCES users(id)
 );
 
+-- user_absences (запланированное отсутствие пользователя; не больше одного на пользователя)
+CREATE TABLE IF NOT EXISTS user_absences (
+    user_id     INTEGER PRIMARY KEY,
+    starts_at   DATETIME NOT NULL,
+    ends_at     DATETIME NOT NULL,
+    handed_over INTEGER NOT NULL DEFAULT 0, -- открытые ревью пользователя уже переданы другим
+    CHECK (starts_at < ends_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
 -- индексы для производительности
 CREATE INDEX IF NOT EXISTS idx_users_team_id
     ON users(team_id);
@@ -79,13 +255,76 @@ CREATE INDEX IF NOT EXISTS idx_pr_author_id
 
 CREATE INDEX IF NOT EXISTS idx_pr_reviewers_reviewer_id
     ON pr_reviewers(reviewer_id);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_exclusions_user_b
+    ON reviewer_exclusions(user_b);
+
+CREATE INDEX IF NOT EXISTS idx_pr_events_pr_id
+    ON pr_events(pr_id);
 `
 
 	if _, err := db.Exec(schema); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
+	// колонки, добавленные после первого релиза: докатываем на старые БД
+	columns := []struct{ table, column, definition string }{
+		{"users", "max_open_reviews", "INTEGER NOT NULL DEFAULT 0"},
+		{"users", "seniority", "TEXT NOT NULL DEFAULT 'middle' CHECK (seniority IN ('junior', 'middle', 'senior'))"},
+		{"users", "timezone", "TEXT NOT NULL DEFAULT ''"},
+		{"users", "work_start", "TEXT NOT NULL DEFAULT ''"},
+		{"users", "work_end", "TEXT NOT NULL DEFAULT ''"},
+		{"users", "quiet_start", "TEXT NOT NULL DEFAULT ''"},
+		{"users", "quiet_end", "TEXT NOT NULL DEFAULT ''"},
+		{"pull_requests", "pending_reviewers", "INTEGER NOT NULL DEFAULT 0"},
+		{"pull_requests", "ready_at", "DATETIME NULL"},
+		{"pull_requests", "closed_at", "DATETIME NULL"},
+		{"pr_reviewers", "source", "TEXT NOT NULL DEFAULT 'team'"},
+		{"pr_reviewers", "source_team_id", "INTEGER NULL"},
+		{"pr_reviewers", "matched_labels", "TEXT NOT NULL DEFAULT '[]'"},
+		{"pr_reviewers", "strategy", "TEXT NOT NULL DEFAULT ''"},
+		{"pr_reviewers", "selection_seed", "INTEGER NULL"},
+		{"pr_reviewers", "verdict", "TEXT NULL CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED'))"},
+		{"pr_reviewers", "verdict_at", "DATETIME NULL"},
+		{"pr_reviewers", "assigned_at", "DATETIME NULL"},
+		{"pr_reviewers", "first_action_at", "DATETIME NULL"},
+		{"pr_reviewers", "completed_at", "DATETIME NULL"},
+		{"team_settings", "fallback_teams", "TEXT NOT NULL DEFAULT '[]'"},
+		{"team_settings", "require_senior", "INTEGER NOT NULL DEFAULT 0"},
+		{"team_settings", "required_approvals", "INTEGER NOT NULL DEFAULT 0"},
+		{"team_settings", "block_on_changes_requested", "INTEGER NOT NULL DEFAULT 0"},
+		{"team_settings", "require_senior_or_owner_approval", "INTEGER NOT NULL DEFAULT 0"},
+		{"team_settings", "review_sla_seconds", "INTEGER NOT NULL DEFAULT 0 CHECK (review_sla_seconds >= 0)"},
+		{"team_settings", "review_sla_action", "TEXT NOT NULL DEFAULT 'FLAG' CHECK (review_sla_action IN ('FLAG', 'REASSIGN'))"},
+	}
+	for _, c := range columns {
+		if err := ensureColumn(db, c.table, c.column, c.definition); err != nil {
+			return nil, fmt.Errorf("%s: migrate %s.%s: %w", op, c.table, c.column, err)
+		}
+	}
+
+	if err := migratePRStatuses(db); err != nil {
+		return nil, fmt.Errorf("%s: migrate pull_requests.status: %w", op, err)
+	}
+
+	// время назначения до появления assigned_at неизвестно — берём момент, когда PR стал OPEN
+	if _, err := db.Exec(`
+        UPDATE pr_reviewers
+        SET assigned_at = (SELECT COALESCE(pr.ready_at, pr.created_at) FROM pull_requests pr WHERE pr.id = pr_reviewers.pr_id)
+        WHERE assigned_at IS NULL`); err != nil {
+		return nil, fmt.Errorf("%s: backfill pr_reviewers.assigned_at: %w", op, err)
+	}
+
+	// до first_action_at и completed_at известен только последний итог — лучшего приближения нет
+	if _, err := db.Exec(`
+        UPDATE pr_reviewers
+        SET first_action_at = verdict_at,
+            completed_at = CASE WHEN verdict IN ('APPROVED', 'CHANGES_REQUESTED') THEN verdict_at END
+        WHERE first_action_at IS NULL AND verdict_at IS NOT NULL`); err != nil {
+		return nil, fmt.Errorf("%s: backfill pr_reviewers review timing: %w", op, err)
+	}
+
+	return &Storage{db: db, selectors: selectors, source: source}, nil
 }
 
 // получаем PL
@@ -99,7 +338,9 @@ func (s *Storage) getPullRequestByExternalID(prID string) (storage.PullRequest,
                au.user_id,      -- внешний author_id
                pr.status,
                pr.created_at,
-               pr.merged_at
+               pr.merged_at,
+               pr.pending_reviewers,
+               pr.closed_at
         FROM pull_requests pr
         JOIN users au ON pr.author_id = au.id
         WHERE pr.pull_request_id = ?`,
@@ -113,21 +354,25 @@ func (s *Storage) getPullRequestByExternalID(prID string) (storage.PullRequest,
 		status           string
 		createdAt        sql.NullTime
 		mergedAt         sql.NullTime
+		pending          int
+		closedAt         sql.NullTime
 	)
 
-	if err := row.Scan(&prExternalID, &name, &authorExternalID, &status, &createdAt, &mergedAt); err != nil {
+	if err := row.Scan(&prExternalID, &name, &authorExternalID, &status, &createdAt, &mergedAt, &pending, &closedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return storage.PullRequest{}, storage.ErrNotFound
 		}
 		return storage.PullRequest{}, fmt.Errorf("%s: scan pr: %w", op, err)
 	}
 
-	// читаем назначенных ревьюверов (user_id)
+	// читаем назначенных ревьюверов (user_id) и откуда они взялись
 	rRows, err := s.db.Query(`
-        SELECT u.user_id
+        SELECT u.user_id, r.source, COALESCE(st.name, ''), r.matched_labels, r.strategy, r.selection_seed,
+               COALESCE(r.verdict, ''), r.verdict_at, r.assigned_at, r.first_action_at, r.completed_at
         FROM pr_reviewers r
         JOIN users u ON r.reviewer_id = u.id
         JOIN pull_requests pr ON r.pr_id = pr.id
+        LEFT JOIN teams st ON r.source_team_id = st.id
         WHERE pr.pull_request_id = ?`,
 		prID,
 	)
@@ -137,16 +382,59 @@ func (s *Storage) getPullRequestByExternalID(prID string) (storage.PullRequest,
 	defer rRows.Close()
 
 	reviewers := make([]string, 0, 2)
+	details := make([]storage.ReviewerAssignment, 0, 2)
 	for rRows.Next() {
-		var uid string
-		if err := rRows.Scan(&uid); err != nil {
+		var a storage.ReviewerAssignment
+		var matchedJSON string
+		var seed sql.NullInt64
+		var verdictAt, assignedAt, firstActionAt, completedAt sql.NullTime
+		if err := rRows.Scan(&a.UserID, &a.Source, &a.TeamName, &matchedJSON, &a.Strategy, &seed, &a.Verdict, &verdictAt,
+			&assignedAt, &firstActionAt, &completedAt); err != nil {
 			return storage.PullRequest{}, fmt.Errorf("%s: scan reviewer: %w", op, err)
 		}
-		reviewers = append(reviewers, uid)
+		if err := json.Unmarshal([]byte(matchedJSON), &a.MatchedLabels); err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: decode matched labels: %w", op, err)
+		}
+		if seed.Valid {
+			v := seed.Int64
+			a.SelectionSeed = &v
+		}
+		if verdictAt.Valid {
+			t := verdictAt.Time
+			a.VerdictAt = &t
+		}
+		a.Timing = &storage.ReviewerTiming{
+			AssignedAt:    nullTimePtr(assignedAt),
+			FirstActionAt: nullTimePtr(firstActionAt),
+			CompletedAt:   nullTimePtr(completedAt),
+		}
+		reviewers = append(reviewers, a.UserID)
+		details = append(details, a)
 	}
 	if err := rRows.Err(); err != nil {
 		return storage.PullRequest{}, fmt.Errorf("%s: reviewers rows err: %w", op, err)
 	}
+	rRows.Close()
+
+	labels, err := prLabels(s.db, prExternalID)
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: query labels: %w", op, err)
+	}
+
+	excluded, err := prExcludedUserIDs(s.db, prExternalID)
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: query excluded reviewers: %w", op, err)
+	}
+
+	declines, err := prDeclines(s.db, prExternalID)
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: query declines: %w", op, err)
+	}
+
+	requested, err := prRequestedUserIDs(s.db, prExternalID)
+	if err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: query requested reviewers: %w", op, err)
+	}
 
 	// конвертим sql.NullTime в *time.Time
 	var createdPtr *time.Time
@@ -161,14 +449,27 @@ func (s *Storage) getPullRequestByExternalID(prID string) (storage.PullRequest,
 		mergedPtr = &t
 	}
 
+	var closedPtr *time.Time
+	if closedAt.Valid {
+		t := closedAt.Time
+		closedPtr = &t
+	}
+
 	return storage.PullRequest{
-		ID:                prExternalID,
-		Name:              name,
-		AuthorID:          authorExternalID,
-		Status:            status,
-		AssignedReviewers: reviewers,
-		CreatedAt:         createdPtr,
-		MergedAt:          mergedPtr,
+		ID:                 prExternalID,
+		Name:               name,
+		AuthorID:           authorExternalID,
+		Status:             status,
+		Labels:             labels,
+		ExcludedReviewers:  excluded,
+		RequestedReviewers: requested,
+		AssignedReviewers:  reviewers,
+		Reviewers:          details,
+		Declines:           declines,
+		PendingReviewers:   pending,
+		CreatedAt:          createdPtr,
+		MergedAt:           mergedPtr,
+		ClosedAt:           closedPtr,
 	}, nil
 }
 
@@ -176,7 +477,46 @@ func (s *Storage) getPullRequestByExternalID(prID string) (storage.PullRequest,
 func (s *Storage) SetUserIsActive(userID string, isActive bool) (storage.User, error) {
 	const op = "storage.sqlite.SetUserIsActive"
 
-	res, err := s.db.Exec("UPDATE users SET is_active = ? WHERE user_id = ?", boolToInt(isActive), userID)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE users SET is_active = ? WHERE user_id = ?", boolToInt(isActive), userID)
+	if err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, _ := res.RowsAffected()
+	if affected == 0 {
+		return storage.User{}, storage.ErrNotFound
+	}
+
+	// активированный пользователь может забрать ожидающие места ревьюверов
+	if isActive {
+		if err := s.fillPendingReviewers(tx); err != nil {
+			return storage.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.getUser(userID)
+}
+
+func (s *Storage) SetUserMaxOpenReviews(userID string, maxOpenReviews int) (storage.User, error) {
+	const op = "storage.sqlite.SetUserMaxOpenReviews"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE users SET max_open_reviews = ? WHERE user_id = ?", maxOpenReviews, userID)
 	if err != nil {
 		return storage.User{}, fmt.Errorf("%s: %w", op, err)
 	}
@@ -186,29 +526,68 @@ func (s *Storage) SetUserIsActive(userID string, isActive bool) (storage.User, e
 		return storage.User{}, storage.ErrNotFound
 	}
 
-	// прочитать юзера вместе с командой
+	// лимит мог вырасти — пробуем заполнить очередь
+	if err := s.fillPendingReviewers(tx); err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.getUser(userID)
+}
+
+// прочитать юзера вместе с командой
+func (s *Storage) getUser(userID string) (storage.User, error) {
+	const op = "storage.sqlite.getUser"
+
 	row := s.db.QueryRow(`
-        SELECT u.user_id, u.username, t.name, u.is_active
+        SELECT u.user_id, u.username, t.name, u.is_active, u.max_open_reviews, u.seniority,
+               u.timezone, u.work_start, u.work_end, u.quiet_start, u.quiet_end
         FROM users u
         JOIN teams t ON u.team_id = t.id
         WHERE u.user_id = ?`, userID)
 
-	var uid, username, teamName string
-	var activeInt int
-	if err := row.Scan(&uid, &username, &teamName, &activeInt); err != nil {
+	var uid, username, teamName, seniority string
+	var timezone, workStart, workEnd, quietStart, quietEnd string
+	var activeInt, maxOpen int
+	if err := row.Scan(&uid, &username, &teamName, &activeInt, &maxOpen, &seniority, &timezone, &workStart, &workEnd, &quietStart, &quietEnd); err != nil {
+		if err == sql.ErrNoRows {
+			return storage.User{}, storage.ErrNotFound
+		}
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	skills, err := userSkills(s.db, uid)
+	if err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	absence, err := userAbsence(s.db, uid)
+	if err != nil {
 		return storage.User{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return storage.User{
-		UserID:   uid,
-		Username: username,
-		TeamName: teamName,
-		IsActive: activeInt == 1,
+		UserID:         uid,
+		Username:       username,
+		TeamName:       teamName,
+		IsActive:       activeInt == 1,
+		MaxOpenReviews: maxOpen,
+		Skills:         skills,
+		Seniority:      seniority,
+		Timezone:       timezone,
+		WorkStart:      workStart,
+		WorkEnd:        workEnd,
+		QuietStart:     quietStart,
+		QuietEnd:       quietEnd,
+		Absence:        absence,
 	}, nil
 }
 
 // pr
-func (s *Storage) CreatePullRequestWithAutoAssign(prID, prName, authorExternalID string) (storage.PullRequest, error) {
+func (s *Storage) CreatePullRequestWithAutoAssign(newPR storage.NewPullRequest) (storage.PullRequest, error) {
 	const op = "storage.sqlite.CreatePullRequestWithAutoAssign"
 
 	tx, err := s.db.Begin()
@@ -219,7 +598,7 @@ func (s *Storage) CreatePullRequestWithAutoAssign(prID, prName, authorExternalID
 
 	// нет ли уже такого PR
 	var tmp int
-	err = tx.QueryRow("SELECT 1 FROM pull_requests WHERE pull_request_id = ?", prID).Scan(&tmp)
+	err = tx.QueryRow("SELECT 1 FROM pull_requests WHERE pull_request_id = ?", newPR.ID).Scan(&tmp)
 	if err == nil {
 		return storage.PullRequest{}, storage.ErrPRExists
 	}
@@ -227,62 +606,63 @@ func (s *Storage) CreatePullRequestWithAutoAssign(prID, prName, authorExternalID
 		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// найти автора
-	var authorID, teamID int64
-	err = tx.QueryRow(`
-        SELECT u.id, u.team_id FROM users u WHERE u.user_id = ?`, authorExternalID,
-	).Scan(&authorID, &teamID)
-	if err == sql.ErrNoRows {
-		return storage.PullRequest{}, storage.ErrNotFound
-	}
+	req, prExcluded, err := newAssignmentRequest(tx, newPR)
 	if err != nil {
 		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// кандидаты: активные из команды автора, не он сам
-	rows, err := tx.Query(`
-        SELECT user_id, id
-        FROM users
-        WHERE team_id = ? AND is_active = 1 AND id != ?`, teamID, authorID)
+	// черновику ревьюверы не назначаются: явно указанные ждут перевода в OPEN
+	var plan assignmentPlan
+	if !newPR.Draft {
+		plan, err = s.planAssignment(tx, req)
+		if err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// создать PR
+	res, err := tx.Exec(`
+        INSERT INTO pull_requests(pull_request_id, name, author_id, status, pending_reviewers, ready_at)
+        VALUES(?, ?, ?, CASE WHEN ? THEN 'DRAFT' ELSE 'OPEN' END, ?, CASE WHEN ? THEN NULL ELSE CURRENT_TIMESTAMP END)`,
+		newPR.ID, newPR.Name, req.authorID, newPR.Draft, plan.pending, newPR.Draft)
 	if err != nil {
 		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
 	}
-	defer rows.Close()
+	prIntID, _ := res.LastInsertId()
 
-	type cand struct {
-		extID string
-		id    int64
+	if err := recordEvent(tx, prIntID, prEvent{typ: storage.EventCreated, actor: newPR.AuthorID}); err != nil {
+		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
 	}
-	var candidates []cand
-	for rows.Next() {
-		var uid string
-		var id int64
-		if err := rows.Scan(&uid, &id); err != nil {
+
+	if newPR.Draft {
+		for _, id := range req.requested {
+			if _, err := tx.Exec(`INSERT INTO pr_requested_reviewers(pr_id, user_id) VALUES(?, ?)`, prIntID, id); err != nil {
+				return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	for _, path := range newPR.ChangedFiles {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO pr_files(pr_id, path) VALUES(?, ?)`, prIntID, path); err != nil {
 			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
 		}
-		candidates = append(candidates, cand{extID: uid, id: id})
 	}
 
-	// перемешать и взять до 2
-	rand.Shuffle(len(candidates), func(i, j int) {
-		candidates[i], candidates[j] = candidates[j], candidates[i]
-	})
-	if len(candidates) > 2 {
-		candidates = candidates[:2]
+	for _, id := range prExcluded {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO pr_excluded_reviewers(pr_id, user_id) VALUES(?, ?)`, prIntID, id); err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
-	// создать PR
-	res, err := tx.Exec(`
-        INSERT INTO pull_requests(pull_request_id, name, author_id, status)
-        VALUES(?, ?, ?, 'OPEN')`, prID, prName, authorID)
-	if err != nil {
-		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+	for _, label := range normalizeTags(newPR.Labels) {
+		if _, err := tx.Exec(`INSERT INTO pr_labels(pr_id, label) VALUES(?, ?)`, prIntID, label); err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+		}
 	}
-	prIntID, _ := res.LastInsertId()
 
 	// вставить ревьюверов
-	for _, c := range candidates {
-		if _, err := tx.Exec(`INSERT INTO pr_reviewers(pr_id, reviewer_id) VALUES(?, ?)`, prIntID, c.id); err != nil {
+	for _, a := range plan.picked {
+		if err := insertAssignment(tx, prIntID, a); err != nil {
 			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
 		}
 	}
@@ -292,9 +672,16 @@ func (s *Storage) CreatePullRequestWithAutoAssign(prID, prName, authorExternalID
 	}
 
 	// собрать полный объект
-	return s.getPullRequestByExternalID(prID)
+	pr, err := s.getPullRequestByExternalID(newPR.ID)
+	if err != nil {
+		return storage.PullRequest{}, err
+	}
+	pr.Warnings = plan.warnings
+	return pr, nil
 }
 
+// MergePullRequest помечает открытый PR как MERGED, если выполнены условия merge команды автора;
+// повторный merge уже смёрженного PR ничего не меняет
 func (s *Storage) MergePullRequest(prID string) (storage.PullRequest, error) {
 	const op = "storage.sqlite.MergePullRequest"
 
@@ -304,11 +691,14 @@ func (s *Storage) MergePullRequest(prID string) (storage.PullRequest, error) {
 	}
 	defer tx.Rollback()
 
-	var intID int64
+	var intID, authorTeamID int64
 	var status string
 	err = tx.QueryRow(`
-        SELECT id, status FROM pull_requests WHERE pull_request_id = ?`, prID,
-	).Scan(&intID, &status)
+        SELECT pr.id, pr.status, au.team_id
+        FROM pull_requests pr
+        JOIN users au ON pr.author_id = au.id
+        WHERE pr.pull_request_id = ?`, prID,
+	).Scan(&intID, &status, &authorTeamID)
 	if err == sql.ErrNoRows {
 		return storage.PullRequest{}, storage.ErrNotFound
 	}
@@ -316,14 +706,35 @@ func (s *Storage) MergePullRequest(prID string) (storage.PullRequest, error) {
 		return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if status != "MERGED" {
+	switch status {
+	case "DRAFT":
+		return storage.PullRequest{}, storage.ErrPRDraft
+	case "CLOSED":
+		return storage.PullRequest{}, storage.ErrPRClosed
+	case "OPEN":
+		unmet, err := unmetMergeConditions(tx, intID, authorTeamID)
+		if err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: merge policy: %w", op, err)
+		}
+		if len(unmet) > 0 {
+			return storage.PullRequest{}, &storage.MergeBlockedError{Conditions: unmet}
+		}
+
 		_, err = tx.Exec(`
             UPDATE pull_requests
-            SET status = 'MERGED', merged_at = CURRENT_TIMESTAMP
+            SET status = 'MERGED', merged_at = CURRENT_TIMESTAMP, pending_reviewers = 0
             WHERE id = ?`, intID)
 		if err != nil {
 			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
 		}
+		if err := recordEvent(tx, intID, prEvent{typ: storage.EventMerged}); err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		// ревьюверы этого PR освободились — раздаём ожидающие места
+		if err := s.fillPendingReviewers(tx); err != nil {
+			return storage.PullRequest{}, fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -333,7 +744,8 @@ func (s *Storage) MergePullRequest(prID string) (storage.PullRequest, error) {
 	return s.getPullRequestByExternalID(prID)
 }
 
-func (s *Storage) ReassignReviewer(prID, oldUserID string) (storage.PullRequest, string, error) {
+// ReassignReviewer заменяет ревьювера; actorID — кто попросил замену (пусто — неизвестно)
+func (s *Storage) ReassignReviewer(prID, oldUserID, actorID string) (storage.PullRequest, string, error) {
 	const op = "storage.sqlite.ReassignReviewer"
 
 	tx, err := s.db.Begin()
@@ -342,35 +754,65 @@ func (s *Storage) ReassignReviewer(prID, oldUserID string) (storage.PullRequest,
 	}
 	defer tx.Rollback()
 
+	replacedBy, err := s.replaceReviewer(tx, prID, oldUserID, storage.EventReasonReassign, actorID)
+	if err != nil {
+		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	pr, err := s.getPullRequestByExternalID(prID)
+	if err != nil {
+		return storage.PullRequest{}, "", err
+	}
+	return pr, replacedBy, nil
+}
+
+// replaceReviewer заменяет назначенного ревьювера активным участником его команды
+// (не автором, не назначенным, не исключённым, не упёршимся в лимит) и возвращает user_id замены.
+// reason и actor попадают в историю PR.
+func (s *Storage) replaceReviewer(tx *sql.Tx, prID, oldUserID, reason, actor string) (string, error) {
 	// найти PR
-	var prIntID, authorIntID int64
+	var prIntID, authorIntID, authorTeamID int64
 	var status string
-	err = tx.QueryRow(`
-        SELECT id, author_id, status FROM pull_requests WHERE pull_request_id = ?`, prID,
-	).Scan(&prIntID, &authorIntID, &status)
+	err := tx.QueryRow(`
+        SELECT pr.id, pr.author_id, au.team_id, pr.status
+        FROM pull_requests pr
+        JOIN users au ON pr.author_id = au.id
+        WHERE pr.pull_request_id = ?`, prID,
+	).Scan(&prIntID, &authorIntID, &authorTeamID, &status)
 	if err == sql.ErrNoRows {
-		return storage.PullRequest{}, "", storage.ErrNotFound
+		return "", storage.ErrNotFound
 	}
 	if err != nil {
-		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+		return "", err
 	}
 
-	if status == "MERGED" {
-		return storage.PullRequest{}, "", storage.ErrPRMerged
+	switch status {
+	case "MERGED":
+		return "", storage.ErrPRMerged
+	case "CLOSED":
+		return "", storage.ErrPRClosed
+	case "DRAFT":
+		return "", storage.ErrPRDraft
 	}
 
 	// найти старого ревьювера
 	var oldIntID, teamID int64
+	var teamName, oldSeniority string
 	err = tx.QueryRow(`
-        SELECT u.id, u.team_id
+        SELECT u.id, u.team_id, t.name, u.seniority
         FROM users u
+        JOIN teams t ON u.team_id = t.id
         WHERE u.user_id = ?`, oldUserID,
-	).Scan(&oldIntID, &teamID)
+	).Scan(&oldIntID, &teamID, &teamName, &oldSeniority)
 	if err == sql.ErrNoRows {
-		return storage.PullRequest{}, "", storage.ErrNotFound
+		return "", storage.ErrNotFound
 	}
 	if err != nil {
-		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+		return "", err
 	}
 
 	// проверить, что он назначен
@@ -380,87 +822,71 @@ func (s *Storage) ReassignReviewer(prID, oldUserID string) (storage.PullRequest,
         WHERE pr_id = ? AND reviewer_id = ?`, prIntID, oldIntID,
 	).Scan(&tmp)
 	if err == sql.ErrNoRows {
-		return storage.PullRequest{}, "", storage.ErrNotAssigned
+		return "", storage.ErrNotAssigned
 	}
 	if err != nil {
-		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+		return "", err
 	}
 
-	// собрать уже назначенных ревьюверов
-	assigned := map[int64]struct{}{}
-	rows, err := tx.Query(`SELECT reviewer_id FROM pr_reviewers WHERE pr_id = ?`, prIntID)
+	// кандидаты: команда старого ревьювера, кроме автора, уже назначенных и исключённых
+	candidates, err := activeCandidates(tx, teamID)
 	if err != nil {
-		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+		return "", err
 	}
-	defer rows.Close()
-	for rows.Next() {
-		var rID int64
-		if err := rows.Scan(&rID); err != nil {
-			return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
-		}
-		assigned[rID] = struct{}{}
+	unavailable, err := unavailableForPR(tx, prIntID, authorIntID)
+	if err != nil {
+		return "", err
 	}
-
-	// кандидаты: команда старого ревьювера
-	candRows, err := tx.Query(`
-        SELECT id, user_id
-        FROM users
-        WHERE team_id = ? AND is_active = 1`, teamID)
+	candidates, _ = splitByCapacity(without(candidates, unavailable))
+	candidates, err = withAuthorHistory(tx, candidates, authorIntID)
 	if err != nil {
-		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+		return "", err
 	}
-	defer candRows.Close()
 
-	type cand struct {
-		id    int64
-		extID string
-	}
-	var candidates []cand
-	for candRows.Next() {
-		var id int64
-		var extID string
-		if err := candRows.Scan(&id, &extID); err != nil {
-			return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
-		}
-		if id == oldIntID {
-			continue
-		}
-		if id == authorIntID {
-			continue
-		}
-		if _, ok := assigned[id]; ok {
-			continue
-		}
-		candidates = append(candidates, cand{id: id, extID: extID})
+	settings, err := teamSettings(tx, teamID)
+	if err != nil {
+		return "", err
 	}
 
-	if len(candidates) == 0 {
-		return storage.PullRequest{}, "", storage.ErrNoCandidate
+	labels, err := prLabels(tx, prID)
+	if err != nil {
+		return "", err
 	}
 
-	rand.Shuffle(len(candidates), func(i, j int) {
-		candidates[i], candidates[j] = candidates[j], candidates[i]
-	})
-	chosen := candidates[0]
-
-	// заменить
-	_, err = tx.Exec(`
-        UPDATE pr_reviewers
-        SET reviewer_id = ?
-        WHERE pr_id = ? AND reviewer_id = ?`, chosen.id, prIntID, oldIntID)
+	// уходит единственный senior при политике «нужен senior» — замена тоже должна быть senior
+	wantSenior, err := seniorWanted(tx, authorTeamID, prIntID, oldIntID)
 	if err != nil {
-		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+		return "", err
+	}
+	if wantSenior && oldSeniority == storage.SenioritySenior {
+		candidates = seniors(candidates)
+		if len(candidates) == 0 {
+			return "", storage.ErrNoSeniorCandidate
+		}
 	}
+	tiers := rankTiers(candidates, replacementRank(labels, wantSenior))
 
-	if err := tx.Commit(); err != nil {
-		return storage.PullRequest{}, "", fmt.Errorf("%s: %w", op, err)
+	seed, rnd := s.draw()
+	picked := selector.SelectTiered(s.selectors.ForTeam(teamName, settings.Strategy), rnd, teamName, tiers, 1)
+	if len(picked) == 0 {
+		return "", storage.ErrNoCandidate
 	}
+	chosen := picked[0]
 
-	pr, err := s.getPullRequestByExternalID(prID)
+	// заменить
+	err = updateAssignment(tx, prIntID, oldIntID, assignment{
+		candidate: chosen,
+		source:    sourceFor(teamID, authorTeamID),
+		teamID:    teamID,
+		matched:   matchedLabels(chosen.Skills, labels),
+		strategy:  s.selectors.NameForTeam(teamName, settings.Strategy),
+		seed:      seed,
+	}, reason, actor)
 	if err != nil {
-		return storage.PullRequest{}, "", err
+		return "", err
 	}
-	return pr, chosen.extID, nil
+
+	return chosen.UserID, nil
 }
 
 func (s *Storage) GetUserReviews(userID string) (storage.UserReviews, error) {
@@ -538,14 +964,25 @@ func (s *Storage) CreateTeam(teamName string, members []storage.TeamMember) (sto
 
 	// Создаём или обновляем пользователей команды
 	for _, m := range members {
+		hasSchedule := m.Timezone != "" || m.WorkStart != "" || m.WorkEnd != ""
+		if hasSchedule {
+			if _, err := workhours.Parse(m.Timezone, m.WorkStart, m.WorkEnd); err != nil {
+				return storage.Team{}, fmt.Errorf("%w: user %s: %s", storage.ErrInvalidWorkHours, m.UserID, err)
+			}
+		}
+
 		var userIntID int64
 		err = tx.QueryRow(`SELECT id FROM users WHERE user_id = ?`, m.UserID).Scan(&userIntID)
 		if err == sql.ErrNoRows {
 			// создаём нового пользователя
+			seniority := m.Seniority
+			if seniority == "" {
+				seniority = storage.DefaultSeniority
+			}
 			_, err = tx.Exec(
-				`INSERT INTO users(user_id, username, team_id, is_active)
-                 VALUES(?, ?, ?, ?)`,
-				m.UserID, m.Username, teamID, boolToInt(m.IsActive),
+				`INSERT INTO users(user_id, username, team_id, is_active, max_open_reviews, seniority)
+                 VALUES(?, ?, ?, ?, COALESCE(?, 0), ?)`,
+				m.UserID, m.Username, teamID, boolToInt(m.IsActive), m.MaxOpenReviews, seniority,
 			)
 			if err != nil {
 				return storage.Team{}, fmt.Errorf("%s: %w", op, err)
@@ -554,9 +991,9 @@ func (s *Storage) CreateTeam(teamName string, members []storage.TeamMember) (sto
 			// обновляем существующего пользователя
 			_, err = tx.Exec(
 				`UPDATE users
-                 SET username = ?, team_id = ?, is_active = ?
+                 SET username = ?, team_id = ?, is_active = ?, max_open_reviews = COALESCE(?, max_open_reviews)
                  WHERE user_id = ?`,
-				m.Username, teamID, boolToInt(m.IsActive), m.UserID,
+				m.Username, teamID, boolToInt(m.IsActive), m.MaxOpenReviews, m.UserID,
 			)
 			if err != nil {
 				return storage.Team{}, fmt.Errorf("%s: %w", op, err)
@@ -564,6 +1001,30 @@ func (s *Storage) CreateTeam(teamName string, members []storage.TeamMember) (sto
 		} else {
 			return storage.Team{}, fmt.Errorf("%s: %w", op, err)
 		}
+
+		if m.Skills != nil {
+			if err := setUserSkills(tx, m.UserID, m.Skills); err != nil {
+				return storage.Team{}, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+		if m.Seniority != "" {
+			if _, err := tx.Exec(`UPDATE users SET seniority = ? WHERE user_id = ?`, m.Seniority, m.UserID); err != nil {
+				return storage.Team{}, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+		if hasSchedule {
+			if _, err := tx.Exec(
+				`UPDATE users SET timezone = ?, work_start = ?, work_end = ? WHERE user_id = ?`,
+				m.Timezone, m.WorkStart, m.WorkEnd, m.UserID,
+			); err != nil {
+				return storage.Team{}, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	// новые участники могут взять ожидающие места
+	if err := s.fillPendingReviewers(tx); err != nil {
+		return storage.Team{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -589,7 +1050,7 @@ func (s *Storage) GetTeam(teamName string) (storage.Team, error) {
 
 	// Выбираем всех пользователей команды
 	rows, err := s.db.Query(
-		`SELECT user_id, username, is_active
+		`SELECT user_id, username, is_active, max_open_reviews, seniority, timezone, work_start, work_end
          FROM users
          WHERE team_id = ?`,
 		teamID,
@@ -605,19 +1066,38 @@ func (s *Storage) GetTeam(teamName string) (storage.Team, error) {
 			userID    string
 			username  string
 			isActiveI int
+			maxOpen   int
+			seniority string
+			timezone  string
+			workStart string
+			workEnd   string
 		)
-		if err := rows.Scan(&userID, &username, &isActiveI); err != nil {
+		if err := rows.Scan(&userID, &username, &isActiveI, &maxOpen, &seniority, &timezone, &workStart, &workEnd); err != nil {
 			return storage.Team{}, fmt.Errorf("%s: %w", op, err)
 		}
 		members = append(members, storage.TeamMember{
-			UserID:   userID,
-			Username: username,
-			IsActive: isActiveI == 1,
+			UserID:         userID,
+			Username:       username,
+			IsActive:       isActiveI == 1,
+			MaxOpenReviews: &maxOpen,
+			Seniority:      seniority,
+			Timezone:       timezone,
+			WorkStart:      workStart,
+			WorkEnd:        workEnd,
 		})
 	}
 	if err := rows.Err(); err != nil {
 		return storage.Team{}, fmt.Errorf("%s: %w", op, err)
 	}
+	rows.Close()
+
+	for i := range members {
+		skills, err := userSkills(s.db, members[i].UserID)
+		if err != nil {
+			return storage.Team{}, fmt.Errorf("%s: %w", op, err)
+		}
+		members[i].Skills = skills
+	}
 
 	return storage.Team{
 		TeamName: teamName,
@@ -725,141 +1205,373 @@ func (s *Storage) BulkDeactivateUsersAndReassign(teamName string, userIDs []stri
 	}
 
 	// Деактивировать этих пользователей
+	ids := make([]int64, 0, len(deactivated))
 	for _, u := range deactivated {
 		if _, err := tx.Exec(`UPDATE users SET is_active = 0 WHERE id = ?`, u.id); err != nil {
 			return storage.BulkDeactivateResult{}, fmt.Errorf("%s: deactivate user %d: %w", op, u.id, err)
 		}
+		ids = append(ids, u.id)
 	}
 
-	// Предзагрузить активных пользователей команды для последующих замен
-	activeRows, err := tx.Query(`
-        SELECT id
-        FROM users
-        WHERE team_id = ? AND is_active = 1
-    `, teamID)
+	handed, err := s.handOverReviews(tx, teamID, teamName, ids, storage.EventReasonDeactivated)
 	if err != nil {
-		return storage.BulkDeactivateResult{}, fmt.Errorf("%s: query active users: %w", op, err)
+		return storage.BulkDeactivateResult{}, fmt.Errorf("%s: %w", op, err)
 	}
-	defer activeRows.Close()
 
-	activeUserIDs := make([]int64, 0)
-	for activeRows.Next() {
-		var id int64
-		if err := activeRows.Scan(&id); err != nil {
-			return storage.BulkDeactivateResult{}, fmt.Errorf("%s: scan active user: %w", op, err)
-		}
-		activeUserIDs = append(activeUserIDs, id)
+	if err := tx.Commit(); err != nil {
+		return storage.BulkDeactivateResult{}, fmt.Errorf("%s: commit: %w", op, err)
 	}
-	if err := activeRows.Err(); err != nil {
-		return storage.BulkDeactivateResult{}, fmt.Errorf("%s: active users rows err: %w", op, err)
+
+	res := storage.BulkDeactivateResult{
+		TeamName:           teamName,
+		DeactivatedUserIDs: make([]string, 0, len(deactivated)),
+		ReassignedCount:    handed.reassigned,
+		RemovedAssignments: handed.removed,
+		SeniorUnavailable:  nonNil(handed.seniorUnavailable),
+	}
+	for _, u := range deactivated {
+		res.DeactivatedUserIDs = append(res.DeactivatedUserIDs, u.extID)
 	}
 
-	reassignedCount := 0
-	removedCount := 0
+	return res, nil
+}
+
+// handOver — итог передачи ревью ушедших пользователей
+type handOver struct {
+	reassigned        int
+	removed           int      // сняты без замены; их места ушли в очередь
+	seniorUnavailable []string // PR, где ушёл единственный нужный senior, а свободного senior'а нет
+}
+
+// handOverReviews передаёт открытые ревью ушедших пользователей другим активным участникам команды.
+// Если замены нет, ревьювер снимается с PR, а его место уходит в очередь (pending_reviewers).
+// Единственного senior'а при политике «нужен senior» заменяет только senior, как в ReassignReviewer.
+func (s *Storage) handOverReviews(tx *sql.Tx, teamID int64, teamName string, userIDs []int64, reason string) (handOver, error) {
+	const op = "storage.sqlite.handOverReviews"
+
+	// Предзагрузить активных пользователей команды для последующих замен
+	activeUsers, err := activeCandidates(tx, teamID)
+	if err != nil {
+		return handOver{}, fmt.Errorf("%s: query active users: %w", op, err)
+	}
+
+	settings, err := teamSettings(tx, teamID)
+	if err != nil {
+		return handOver{}, fmt.Errorf("%s: team settings: %w", op, err)
+	}
+	sel := s.selectors.ForTeam(teamName, settings.Strategy)
+
+	var res handOver
+	for _, userID := range userIDs {
+		var seniority string
+		if err := tx.QueryRow(`SELECT seniority FROM users WHERE id = ?`, userID).Scan(&seniority); err != nil {
+			return handOver{}, fmt.Errorf("%s: query seniority of %d: %w", op, userID, err)
+		}
 
-	for _, u := range deactivated {
 		prRows, err := tx.Query(`
-            SELECT pr.id, pr.author_id
+            SELECT pr.id, pr.pull_request_id, pr.author_id, au.team_id
             FROM pr_reviewers r
             JOIN pull_requests pr ON r.pr_id = pr.id
+            JOIN users au ON pr.author_id = au.id
             WHERE r.reviewer_id = ? AND pr.status = 'OPEN'
-        `, u.id)
+        `, userID)
 		if err != nil {
-			return storage.BulkDeactivateResult{}, fmt.Errorf("%s: query prs for reviewer %d: %w", op, u.id, err)
+			return handOver{}, fmt.Errorf("%s: query prs for reviewer %d: %w", op, userID, err)
 		}
 
 		for prRows.Next() {
-			var prIntID, authorIntID int64
-			if err := prRows.Scan(&prIntID, &authorIntID); err != nil {
+			var prIntID, authorIntID, authorTeamID int64
+			var prExtID string
+			if err := prRows.Scan(&prIntID, &prExtID, &authorIntID, &authorTeamID); err != nil {
 				prRows.Close()
-				return storage.BulkDeactivateResult{}, fmt.Errorf("%s: scan pr for reviewer %d: %w", op, u.id, err)
+				return handOver{}, fmt.Errorf("%s: scan pr for reviewer %d: %w", op, userID, err)
 			}
 
-			assignedRows, err := tx.Query(`
-                SELECT reviewer_id FROM pr_reviewers WHERE pr_id = ?
-            `, prIntID)
+			unavailable, err := unavailableForPR(tx, prIntID, authorIntID)
 			if err != nil {
 				prRows.Close()
-				return storage.BulkDeactivateResult{}, fmt.Errorf("%s: query assigned reviewers: %w", op, err)
+				return handOver{}, fmt.Errorf("%s: query assigned reviewers: %w", op, err)
 			}
 
-			assigned := make(map[int64]struct{})
-			for assignedRows.Next() {
-				var rid int64
-				if err := assignedRows.Scan(&rid); err != nil {
-					assignedRows.Close()
-					prRows.Close()
-					return storage.BulkDeactivateResult{}, fmt.Errorf("%s: scan assigned reviewer: %w", op, err)
-				}
-				assigned[rid] = struct{}{}
+			labels, err := prLabels(tx, prExtID)
+			if err != nil {
+				prRows.Close()
+				return handOver{}, fmt.Errorf("%s: query labels: %w", op, err)
 			}
-			assignedRows.Close()
-			if err := assignedRows.Err(); err != nil {
+			wantSenior, err := seniorWanted(tx, authorTeamID, prIntID, userID)
+			if err != nil {
 				prRows.Close()
-				return storage.BulkDeactivateResult{}, fmt.Errorf("%s: assigned rows err: %w", op, err)
+				return handOver{}, fmt.Errorf("%s: senior policy: %w", op, err)
 			}
 
-			delete(assigned, u.id)
-
-			candidates := make([]int64, 0, len(activeUserIDs))
-			for _, candID := range activeUserIDs {
-				if candID == authorIntID {
-					continue
-				}
-				if _, ok := assigned[candID]; ok {
-					continue
-				}
-				candidates = append(candidates, candID)
+			free, _ := splitByCapacity(without(activeUsers, unavailable))
+			free, err = withAuthorHistory(tx, free, authorIntID)
+			if err != nil {
+				prRows.Close()
+				return handOver{}, fmt.Errorf("%s: query author history: %w", op, err)
+			}
+			seniorOnly := wantSenior && seniority == storage.SenioritySenior
+			if seniorOnly {
+				free = seniors(free)
 			}
+			tiers := rankTiers(free, replacementRank(labels, wantSenior))
+			seed, rnd := s.draw()
+			candidates := selector.SelectTiered(sel, rnd, teamName, tiers, 1)
 
 			if len(candidates) == 0 {
 				if _, err := tx.Exec(
 					`DELETE FROM pr_reviewers WHERE pr_id = ? AND reviewer_id = ?`,
-					prIntID, u.id,
+					prIntID, userID,
+				); err != nil {
+					prRows.Close()
+					return handOver{}, fmt.Errorf("%s: delete reviewer from pr: %w", op, err)
+				}
+				if _, err := tx.Exec(
+					`UPDATE pull_requests SET pending_reviewers = pending_reviewers + 1 WHERE id = ?`,
+					prIntID,
 				); err != nil {
 					prRows.Close()
-					return storage.BulkDeactivateResult{}, fmt.Errorf("%s: delete reviewer from pr: %w", op, err)
+					return handOver{}, fmt.Errorf("%s: queue reviewer slot: %w", op, err)
+				}
+				if err := recordEvent(tx, prIntID, prEvent{
+					typ:    storage.EventReviewerRemoved,
+					userID: userID,
+					reason: reason,
+					actor:  storage.EventActorSystem,
+				}); err != nil {
+					prRows.Close()
+					return handOver{}, fmt.Errorf("%s: record removal: %w", op, err)
+				}
+				res.removed++
+				if seniorOnly {
+					res.seniorUnavailable = append(res.seniorUnavailable, prExtID)
 				}
-				removedCount++
 			} else {
-				rand.Shuffle(len(candidates), func(i, j int) {
-					candidates[i], candidates[j] = candidates[j], candidates[i]
-				})
 				chosen := candidates[0]
 
-				if _, err := tx.Exec(
-					`UPDATE pr_reviewers SET reviewer_id = ? WHERE pr_id = ? AND reviewer_id = ?`,
-					chosen, prIntID, u.id,
-				); err != nil {
+				// учитываем новое назначение, чтобы следующие замены видели актуальную нагрузку
+				addOpenReviews(activeUsers, chosen.ID, 1)
+
+				if err := updateAssignment(tx, prIntID, userID, assignment{
+					candidate: chosen,
+					source:    sourceFor(teamID, authorTeamID),
+					teamID:    teamID,
+					matched:   matchedLabels(chosen.Skills, labels),
+					strategy:  s.selectors.NameForTeam(teamName, settings.Strategy),
+					seed:      seed,
+				}, reason, storage.EventActorSystem); err != nil {
 					prRows.Close()
-					return storage.BulkDeactivateResult{}, fmt.Errorf("%s: update reviewer in pr: %w", op, err)
+					return handOver{}, fmt.Errorf("%s: update reviewer in pr: %w", op, err)
 				}
-				reassignedCount++
+				res.reassigned++
 			}
 		}
 		prRows.Close()
 		if err := prRows.Err(); err != nil {
-			return storage.BulkDeactivateResult{}, fmt.Errorf("%s: prs rows err: %w", op, err)
+			return handOver{}, fmt.Errorf("%s: prs rows err: %w", op, err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return storage.BulkDeactivateResult{}, fmt.Errorf("%s: commit: %w", op, err)
+	return res, nil
+}
+
+// activeCandidates возвращает активных и не отсутствующих сейчас участников команды
+// вместе с количеством открытых PR, которые они сейчас ревьюят
+func activeCandidates(q querier, teamID int64) ([]selector.Candidate, error) {
+	now := time.Now()
+
+	// порядок важен: с тем же сидом выбор должен повторяться
+	rows, err := q.Query(`
+        SELECT u.id,
+               u.user_id,
+               (SELECT COUNT(*)
+                FROM pr_reviewers r
+                JOIN pull_requests pr ON r.pr_id = pr.id
+                WHERE r.reviewer_id = u.id AND pr.status = 'OPEN') AS open_reviews,
+               u.max_open_reviews,
+               u.seniority,
+               u.timezone,
+               u.work_start,
+               u.work_end
+        FROM users u
+        WHERE u.team_id = ? AND u.is_active = 1
+          AND NOT EXISTS (SELECT 1 FROM user_absences a
+                          WHERE a.user_id = u.id AND a.starts_at <= ? AND a.ends_at > ?)
+        ORDER BY u.id`, teamID, sqlTime(now), sqlTime(now))
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	res := storage.BulkDeactivateResult{
-		TeamName:           teamName,
-		DeactivatedUserIDs: make([]string, 0, len(deactivated)),
-		ReassignedCount:    reassignedCount,
-		RemovedAssignments: removedCount,
+	var candidates []selector.Candidate
+	for rows.Next() {
+		var c selector.Candidate
+		var timezone, workStart, workEnd string
+		if err := rows.Scan(&c.ID, &c.UserID, &c.OpenReviews, &c.MaxOpenReviews, &c.Seniority, &timezone, &workStart, &workEnd); err != nil {
+			return nil, err
+		}
+		schedule, err := workhours.Parse(timezone, workStart, workEnd)
+		if err != nil {
+			return nil, fmt.Errorf("user %s: %w", c.UserID, err)
+		}
+		c.AvailableIn = schedule.Until(now)
+		candidates = append(candidates, c)
 	}
-	for _, u := range deactivated {
-		res.DeactivatedUserIDs = append(res.DeactivatedUserIDs, u.extID)
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
+	rows.Close()
 
-	return res, nil
+	// навыки команды одним запросом
+	skillRows, err := q.Query(`
+        SELECT s.user_id, s.skill
+        FROM user_skills s
+        JOIN users u ON s.user_id = u.id
+        WHERE u.team_id = ?
+        ORDER BY s.skill`, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer skillRows.Close()
+
+	skills := make(map[int64][]string)
+	for skillRows.Next() {
+		var id int64
+		var skill string
+		if err := skillRows.Scan(&id, &skill); err != nil {
+			return nil, err
+		}
+		skills[id] = append(skills[id], skill)
+	}
+	for i := range candidates {
+		candidates[i].Skills = skills[candidates[i].ID]
+	}
+
+	return candidates, skillRows.Err()
 }
 
+// without отбрасывает кандидатов с указанными внутренними id
+func without(candidates []selector.Candidate, skip map[int64]struct{}) []selector.Candidate {
+	out := make([]selector.Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if _, ok := skip[c.ID]; ok {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// splitByCapacity делит кандидатов на тех, кого ещё можно назначить, и упёршихся в лимит
+func splitByCapacity(candidates []selector.Candidate) (free, full []selector.Candidate) {
+	for _, c := range candidates {
+		if c.AtCapacity() {
+			full = append(full, c)
+			continue
+		}
+		free = append(free, c)
+	}
+	return free, full
+}
+
+// addOpenReviews корректирует нагрузку кандидата в уже загруженном списке
+func addOpenReviews(candidates []selector.Candidate, id int64, delta int) {
+	for i := range candidates {
+		if candidates[i].ID == id {
+			candidates[i].OpenReviews += delta
+			return
+		}
+	}
+}
+
+// ensureColumn добавляет колонку, если её ещё нет в таблице
+func ensureColumn(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+	return err
+}
+
+// prStatusCheck — ограничение статуса PR до появления DRAFT и CLOSED
+const prStatusCheck = `CHECK (status IN ('OPEN', 'MERGED'))`
+
+// migratePRStatuses пересоздаёт pull_requests со старым CHECK на status: SQLite не умеет менять ограничения.
+// Таблица копируется по её же DDL с новым CHECK, поэтому колонки, докатанные ensureColumn, сохраняются.
+func migratePRStatuses(db *sql.DB) error {
+	var ddl string
+	if err := db.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'pull_requests'`).Scan(&ddl); err != nil {
+		return err
+	}
+	if !strings.Contains(ddl, prStatusCheck) {
+		return nil
+	}
+
+	newDDL := strings.Replace(ddl, prStatusCheck, `CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED'))`, 1)
+	newDDL = strings.Replace(newDDL, "pull_requests", "pull_requests_new", 1)
+
+	// PRAGMA foreign_keys действует на соединение и не меняется внутри транзакции:
+	// выключаем его на выделенном соединении, чтобы DROP не удалил ревьюверов каскадом
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, `PRAGMA foreign_keys = ON`)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmts := []string{
+		newDDL,
+		`INSERT INTO pull_requests_new SELECT * FROM pull_requests`,
+		`DROP TABLE pull_requests`,
+		`ALTER TABLE pull_requests_new RENAME TO pull_requests`,
+		`CREATE INDEX IF NOT EXISTS idx_pr_pull_request_id ON pull_requests(pull_request_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_pr_author_id ON pull_requests(author_id)`,
+		// до черновиков каждый PR сразу был готов к ревью
+		`UPDATE pull_requests SET ready_at = created_at WHERE ready_at IS NULL`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
 
 func boolToInt(b bool) int {
 	if b {
@@ -867,3 +1579,11 @@ func boolToInt(b bool) int {
 	}
 	return 0
 }
+
+// nullTimePtr — NULL как nil
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"pr-service/internal/selector"
+	"pr-service/internal/storage"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newTestStorage — хранилище в отдельном файле БД, без планировщика
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	selectors, err := selector.NewRegistry(selector.StrategyRandom, nil, 0)
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), selectors, selector.NewSource(1))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	t.Cleanup(func() { s.db.Close() })
+
+	return s
+}
+
+// mustCreateTeam создаёт команду из активных участников с reviewerCount ревьюверами на PR
+func mustCreateTeam(t *testing.T, s *Storage, teamName string, reviewerCount int, userIDs ...string) {
+	t.Helper()
+
+	members := make([]storage.TeamMember, 0, len(userIDs))
+	for _, id := range userIDs {
+		members = append(members, storage.TeamMember{UserID: id, Username: id, IsActive: true})
+	}
+	if _, err := s.CreateTeam(teamName, members); err != nil {
+		t.Fatalf("create team %s: %v", teamName, err)
+	}
+	if _, err := s.UpdateTeamSettings(teamName, storage.TeamSettingsUpdate{ReviewerCount: &reviewerCount}); err != nil {
+		t.Fatalf("team settings %s: %v", teamName, err)
+	}
+}
+
+// mustCreatePR открывает PR; без reviewers ревьюверы назначаются автоматически
+func mustCreatePR(t *testing.T, s *Storage, prID, authorID string, reviewers ...string) storage.PullRequest {
+	t.Helper()
+
+	pr, err := s.CreatePullRequestWithAutoAssign(storage.NewPullRequest{
+		ID:                 prID,
+		Name:               prID,
+		AuthorID:           authorID,
+		RequestedReviewers: reviewers,
+	})
+	if err != nil {
+		t.Fatalf("create pr %s: %v", prID, err)
+	}
+	return pr
+}
+
+// mustGetPR читает PR по внешнему id
+func mustGetPR(t *testing.T, s *Storage, prID string) storage.PullRequest {
+	t.Helper()
+
+	pr, err := s.getPullRequestByExternalID(prID)
+	if err != nil {
+		t.Fatalf("get pr %s: %v", prID, err)
+	}
+	return pr
+}
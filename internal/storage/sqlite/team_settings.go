@@ -0,0 +1,217 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"pr-service/internal/storage"
+	"time"
+)
+
+// GetTeamSettings возвращает настройки команды (значения по умолчанию, если их не меняли)
+func (s *Storage) GetTeamSettings(teamName string) (storage.TeamSettings, error) {
+	const op = "storage.sqlite.GetTeamSettings"
+
+	var teamID int64
+	err := s.db.QueryRow(`SELECT id FROM teams WHERE name = ?`, teamName).Scan(&teamID)
+	if err == sql.ErrNoRows {
+		return storage.TeamSettings{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return storage.TeamSettings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	settings, err := teamSettings(s.db, teamID)
+	if err != nil {
+		return storage.TeamSettings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.withEffectiveStrategy(teamName, settings), nil
+}
+
+// UpdateTeamSettings меняет только переданные поля настроек команды.
+// Изменённый SLA ревью применяется сразу, не дожидаясь планировщика.
+func (s *Storage) UpdateTeamSettings(teamName string, upd storage.TeamSettingsUpdate) (storage.TeamSettings, error) {
+	const op = "storage.sqlite.UpdateTeamSettings"
+
+	if upd.ReviewerCount != nil && *upd.ReviewerCount < 1 {
+		return storage.TeamSettings{}, fmt.Errorf("%w: reviewer_count must be at least 1", storage.ErrInvalidSettings)
+	}
+	if upd.RequiredApprovals != nil && *upd.RequiredApprovals < 0 {
+		return storage.TeamSettings{}, fmt.Errorf("%w: required_approvals must not be negative", storage.ErrInvalidSettings)
+	}
+	if upd.ReviewSLA != nil && (*upd.ReviewSLA < 0 || *upd.ReviewSLA%time.Second != 0) {
+		return storage.TeamSettings{}, fmt.Errorf("%w: review_sla must be a non-negative whole number of seconds", storage.ErrInvalidSettings)
+	}
+	if upd.ReviewSLAAction != nil && *upd.ReviewSLAAction != storage.SLAActionFlag && *upd.ReviewSLAAction != storage.SLAActionReassign {
+		return storage.TeamSettings{}, fmt.Errorf("%w: unknown review_sla_action %q", storage.ErrInvalidSettings, *upd.ReviewSLAAction)
+	}
+	if upd.Strategy != nil && *upd.Strategy != "" && !s.selectors.Has(*upd.Strategy) {
+		return storage.TeamSettings{}, fmt.Errorf("%w: unknown strategy %q", storage.ErrInvalidSettings, *upd.Strategy)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.TeamSettings{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var teamID int64
+	err = tx.QueryRow(`SELECT id FROM teams WHERE name = ?`, teamName).Scan(&teamID)
+	if err == sql.ErrNoRows {
+		return storage.TeamSettings{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return storage.TeamSettings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	settings, err := teamSettings(tx, teamID)
+	if err != nil {
+		return storage.TeamSettings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if upd.ReviewerCount != nil {
+		settings.ReviewerCount = *upd.ReviewerCount
+	}
+	if upd.Strategy != nil {
+		settings.Strategy = *upd.Strategy
+	}
+	if upd.AllowCrossTeamFallback != nil {
+		settings.AllowCrossTeamFallback = *upd.AllowCrossTeamFallback
+	}
+	if upd.FallbackTeams != nil {
+		if err := validateFallbackTeams(tx, teamName, *upd.FallbackTeams); err != nil {
+			return storage.TeamSettings{}, err
+		}
+		settings.FallbackTeams = *upd.FallbackTeams
+	}
+	if upd.RequireSenior != nil {
+		settings.RequireSenior = *upd.RequireSenior
+	}
+	if upd.RequiredApprovals != nil {
+		settings.MergePolicy.RequiredApprovals = *upd.RequiredApprovals
+	}
+	if upd.BlockOnChangesRequested != nil {
+		settings.MergePolicy.BlockOnChangesRequested = *upd.BlockOnChangesRequested
+	}
+	if upd.RequireSeniorOrOwnerApproval != nil {
+		settings.MergePolicy.RequireSeniorOrOwnerApproval = *upd.RequireSeniorOrOwnerApproval
+	}
+	if upd.ReviewSLA != nil {
+		settings.ReviewSLA.Duration = *upd.ReviewSLA
+	}
+	if upd.ReviewSLAAction != nil {
+		settings.ReviewSLA.Action = *upd.ReviewSLAAction
+	}
+
+	fallbackJSON, err := json.Marshal(settings.FallbackTeams)
+	if err != nil {
+		return storage.TeamSettings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO team_settings(team_id, reviewer_count, strategy, allow_cross_team, fallback_teams, require_senior,
+                                  required_approvals, block_on_changes_requested, require_senior_or_owner_approval,
+                                  review_sla_seconds, review_sla_action)
+        VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT(team_id) DO UPDATE SET
+            reviewer_count   = excluded.reviewer_count,
+            strategy         = excluded.strategy,
+            allow_cross_team = excluded.allow_cross_team,
+            fallback_teams   = excluded.fallback_teams,
+            require_senior   = excluded.require_senior,
+            required_approvals               = excluded.required_approvals,
+            block_on_changes_requested       = excluded.block_on_changes_requested,
+            require_senior_or_owner_approval = excluded.require_senior_or_owner_approval,
+            review_sla_seconds = excluded.review_sla_seconds,
+            review_sla_action  = excluded.review_sla_action`,
+		teamID, settings.ReviewerCount, settings.Strategy, boolToInt(settings.AllowCrossTeamFallback), string(fallbackJSON),
+		boolToInt(settings.RequireSenior),
+		settings.MergePolicy.RequiredApprovals, boolToInt(settings.MergePolicy.BlockOnChangesRequested),
+		boolToInt(settings.MergePolicy.RequireSeniorOrOwnerApproval),
+		int64(settings.ReviewSLA.Duration/time.Second), settings.ReviewSLA.Action,
+	)
+	if err != nil {
+		return storage.TeamSettings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.TeamSettings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.withEffectiveStrategy(teamName, settings), nil
+}
+
+func (s *Storage) withEffectiveStrategy(teamName string, settings storage.TeamSettings) storage.TeamSettings {
+	settings.TeamName = teamName
+	settings.EffectiveStrategy = s.selectors.NameForTeam(teamName, settings.Strategy)
+	return settings
+}
+
+// validateFallbackTeams: запасные команды должны существовать, не повторяться и не совпадать с самой командой
+func validateFallbackTeams(q querier, teamName string, fallback []string) error {
+	seen := make(map[string]struct{}, len(fallback))
+	for _, name := range fallback {
+		if name == teamName {
+			return fmt.Errorf("%w: team cannot fall back to itself", storage.ErrInvalidSettings)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("%w: fallback team %q is listed twice", storage.ErrInvalidSettings, name)
+		}
+		seen[name] = struct{}{}
+
+		var tmp int
+		err := q.QueryRow(`SELECT 1 FROM teams WHERE name = ?`, name).Scan(&tmp)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: fallback team %q not found", storage.ErrInvalidSettings, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// teamSettings читает настройки команды; если строки нет — значения по умолчанию
+func teamSettings(q querier, teamID int64) (storage.TeamSettings, error) {
+	settings := storage.TeamSettings{
+		ReviewerCount: storage.DefaultReviewerCount,
+		FallbackTeams: []string{},
+		ReviewSLA:     storage.ReviewSLA{Action: storage.SLAActionFlag},
+	}
+
+	var (
+		allowCrossTeam int
+		fallbackJSON   string
+		requireSenior  int
+		blockOnChanges int
+		seniorOrOwner  int
+		slaSeconds     int64
+	)
+	err := q.QueryRow(`
+        SELECT reviewer_count, strategy, allow_cross_team, fallback_teams, require_senior,
+               required_approvals, block_on_changes_requested, require_senior_or_owner_approval,
+               review_sla_seconds, review_sla_action
+        FROM team_settings
+        WHERE team_id = ?`, teamID,
+	).Scan(&settings.ReviewerCount, &settings.Strategy, &allowCrossTeam, &fallbackJSON, &requireSenior,
+		&settings.MergePolicy.RequiredApprovals, &blockOnChanges, &seniorOrOwner,
+		&slaSeconds, &settings.ReviewSLA.Action)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return storage.TeamSettings{}, err
+	}
+
+	settings.AllowCrossTeamFallback = allowCrossTeam == 1
+	settings.RequireSenior = requireSenior == 1
+	settings.MergePolicy.BlockOnChangesRequested = blockOnChanges == 1
+	settings.MergePolicy.RequireSeniorOrOwnerApproval = seniorOrOwner == 1
+	settings.ReviewSLA.Duration = time.Duration(slaSeconds) * time.Second
+	if err := json.Unmarshal([]byte(fallbackJSON), &settings.FallbackTeams); err != nil {
+		return storage.TeamSettings{}, fmt.Errorf("decode fallback_teams: %w", err)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"pr-service/internal/lib/workhours"
+	"pr-service/internal/storage"
+	"strings"
+)
+
+// UpdateUser меняет только переданные поля пользователя
+func (s *Storage) UpdateUser(userID string, upd storage.UserUpdate) (storage.User, error) {
+	const op = "storage.sqlite.UpdateUser"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var tmp int
+	err = tx.QueryRow(`SELECT 1 FROM users WHERE user_id = ?`, userID).Scan(&tmp)
+	if err == sql.ErrNoRows {
+		return storage.User{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if upd.Username != nil {
+		if _, err := tx.Exec(`UPDATE users SET username = ? WHERE user_id = ?`, *upd.Username, userID); err != nil {
+			return storage.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if upd.IsActive != nil {
+		if _, err := tx.Exec(`UPDATE users SET is_active = ? WHERE user_id = ?`, boolToInt(*upd.IsActive), userID); err != nil {
+			return storage.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if upd.MaxOpenReviews != nil {
+		if _, err := tx.Exec(`UPDATE users SET max_open_reviews = ? WHERE user_id = ?`, *upd.MaxOpenReviews, userID); err != nil {
+			return storage.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if upd.Skills != nil {
+		if err := setUserSkills(tx, userID, *upd.Skills); err != nil {
+			return storage.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if upd.Seniority != nil {
+		if _, err := tx.Exec(`UPDATE users SET seniority = ? WHERE user_id = ?`, *upd.Seniority, userID); err != nil {
+			return storage.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if upd.Timezone != nil || upd.WorkStart != nil || upd.WorkEnd != nil || upd.QuietStart != nil || upd.QuietEnd != nil {
+		if err := updateSchedule(tx, userID, upd); err != nil {
+			return storage.User{}, err
+		}
+	}
+
+	// активность или лимит могли измениться — пробуем заполнить очередь
+	if err := s.fillPendingReviewers(tx); err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.getUser(userID)
+}
+
+// updateSchedule накладывает переданные поля расписания и тихих часов на текущие и проверяет результат целиком
+func updateSchedule(q querier, userID string, upd storage.UserUpdate) error {
+	const op = "storage.sqlite.updateSchedule"
+
+	var timezone, workStart, workEnd, quietStart, quietEnd string
+	err := q.QueryRow(
+		`SELECT timezone, work_start, work_end, quiet_start, quiet_end FROM users WHERE user_id = ?`, userID,
+	).Scan(&timezone, &workStart, &workEnd, &quietStart, &quietEnd)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if upd.Timezone != nil {
+		timezone = *upd.Timezone
+	}
+	if upd.WorkStart != nil {
+		workStart = *upd.WorkStart
+	}
+	if upd.WorkEnd != nil {
+		workEnd = *upd.WorkEnd
+	}
+	if upd.QuietStart != nil {
+		quietStart = *upd.QuietStart
+	}
+	if upd.QuietEnd != nil {
+		quietEnd = *upd.QuietEnd
+	}
+
+	if _, err := workhours.Parse(timezone, workStart, workEnd); err != nil {
+		return fmt.Errorf("%w: %s", storage.ErrInvalidWorkHours, err)
+	}
+	if _, err := workhours.ParseQuiet(timezone, quietStart, quietEnd); err != nil {
+		return fmt.Errorf("%w: %s", storage.ErrInvalidQuietHours, err)
+	}
+
+	_, err = q.Exec(
+		`UPDATE users SET timezone = ?, work_start = ?, work_end = ?, quiet_start = ?, quiet_end = ? WHERE user_id = ?`,
+		timezone, workStart, workEnd, quietStart, quietEnd, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// setUserSkills заменяет навыки пользователя
+func setUserSkills(q querier, userID string, skills []string) error {
+	if _, err := q.Exec(`
+        DELETE FROM user_skills
+        WHERE user_id = (SELECT id FROM users WHERE user_id = ?)`, userID); err != nil {
+		return err
+	}
+
+	for _, skill := range normalizeTags(skills) {
+		if _, err := q.Exec(`
+            INSERT INTO user_skills(user_id, skill)
+            SELECT id, ? FROM users WHERE user_id = ?`, skill, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// userSkills — навыки пользователя по внешнему user_id
+func userSkills(q querier, userID string) ([]string, error) {
+	return stringColumn(q, `
+        SELECT s.skill
+        FROM user_skills s
+        JOIN users u ON s.user_id = u.id
+        WHERE u.user_id = ?
+        ORDER BY s.skill`, userID)
+}
+
+// prLabels — метки PR по внешнему pull_request_id
+func prLabels(q querier, prID string) ([]string, error) {
+	return stringColumn(q, `
+        SELECT l.label
+        FROM pr_labels l
+        JOIN pull_requests pr ON l.pr_id = pr.id
+        WHERE pr.pull_request_id = ?
+        ORDER BY l.label`, prID)
+}
+
+// stringColumn читает единственную строковую колонку результата
+func stringColumn(q querier, query string, args ...any) ([]string, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []string{}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+
+	return out, rows.Err()
+}
+
+// normalizeTags приводит навыки и метки к нижнему регистру, убирает пустые и повторы
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]struct{}, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out
+}
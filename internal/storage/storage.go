@@ -2,6 +2,7 @@ package storage
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -12,33 +13,207 @@ var (
 	ErrPRMerged    = errors.New("pull request already merged")
 	ErrNotAssigned = errors.New("reviewer is not assigned to this PR")
 	ErrNoCandidate = errors.New("no active replacement candidate in team")
+
+	// ErrPRClosed и ErrPRDraft — действие недоступно в этом статусе PR (как ErrPRMerged)
+	ErrPRClosed = errors.New("pull request is closed")
+	ErrPRDraft  = errors.New("pull request is a draft")
+
+	// ErrInvalidTransition — переход между статусами PR не разрешён
+	ErrInvalidTransition = errors.New("invalid pull request status transition")
+
+	// ErrNoSeniorCandidate — заменяемый ревьювер был единственным senior, а замены-senior нет
+	ErrNoSeniorCandidate = errors.New("no senior replacement candidate in team")
+
+	ErrInvalidSettings   = errors.New("invalid team settings")
+	ErrInvalidCodeOwners = errors.New("invalid CODEOWNERS")
+	ErrInvalidExclusion  = errors.New("invalid reviewer exclusion")
+	ErrInvalidWorkHours  = errors.New("invalid working hours")
+	ErrInvalidQuietHours = errors.New("invalid quiet hours")
+	ErrInvalidAbsence    = errors.New("invalid absence period")
+
+	ErrInvalidDeclineReason = errors.New("invalid decline reason")
+	ErrInvalidVerdict       = errors.New("invalid review verdict")
+
+	ErrInvalidFilter = errors.New("invalid pull request filter")
+
+	// ErrMergeBlocked — не выполнены условия merge команды; подробности в MergeBlockedError
+	ErrMergeBlocked = errors.New("merge conditions are not met")
+
+	// ErrInvalidReviewers — явно указанных ревьюверов нельзя назначить; подробности в InvalidReviewersError
+	ErrInvalidReviewers = errors.New("invalid requested reviewers")
+)
+
+// Размер страницы ListPullRequests
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 100
+)
+
+// DefaultReviewerCount — сколько ревьюверов назначается, если команда не настроила иное
+const DefaultReviewerCount = 2
+
+// Уровни пользователей
+const (
+	SeniorityJunior = "junior"
+	SeniorityMiddle = "middle"
+	SenioritySenior = "senior"
+)
+
+// DefaultSeniority — уровень новых пользователей, если он не указан
+const DefaultSeniority = SeniorityMiddle
+
+// ValidSeniority сообщает, известен ли уровень
+func ValidSeniority(s string) bool {
+	switch s {
+	case SeniorityJunior, SeniorityMiddle, SenioritySenior:
+		return true
+	}
+	return false
+}
+
+// Предупреждения политик назначения: PR создан, но политика команды не выполнена
+const (
+	WarningSeniorUnavailable = "SENIOR_REVIEWER_UNAVAILABLE"
+	// явно указанный для черновика ревьювер к переводу в OPEN стал недоступен; его место занято автоназначением
+	WarningRequestedReviewerUnavailable = "REQUESTED_REVIEWER_UNAVAILABLE"
+)
+
+// Почему пользователь не может ревьюить PR (см. PreviewAssignment и NewPullRequest.RequestedReviewers)
+const (
+	ExclusionReasonNotFound      = "NOT_FOUND"       // такого пользователя нет
+	ExclusionReasonNotTeamMember = "NOT_TEAM_MEMBER" // не из команды автора
+	ExclusionReasonAuthor        = "AUTHOR"          // автор PR
+	ExclusionReasonExcluded      = "EXCLUDED_FOR_PR" // в excluded_reviewers PR
+	ExclusionReasonPair          = "EXCLUSION_PAIR"  // в паре-исключении с автором
+	ExclusionReasonInactive      = "INACTIVE"        // is_active = false
+	ExclusionReasonAbsent        = "ABSENT"          // сейчас отсутствует
+	ExclusionReasonAtCapacity    = "AT_CAPACITY"     // упёрся в max_open_reviews
+)
+
+// Откуда взялся ревьювер
+const (
+	ReviewerSourceTeam      = "team"      // команда автора
+	ReviewerSourceCodeOwner = "codeowner" // владелец изменённых путей по CODEOWNERS
+	ReviewerSourceFallback  = "fallback"  // запасная команда
+	ReviewerSourceRequested = "requested" // указан автором при создании PR
+)
+
+// Почему ревьювер отказался от ревью
+const (
+	DeclineReasonConflict    = "CONFLICT"     // конфликт интересов
+	DeclineReasonNoExpertise = "NO_EXPERTISE" // не разбирается в изменениях
+	DeclineReasonOverloaded  = "OVERLOADED"   // перегружен
+)
+
+// Невыполненные условия merge (см. MergePolicy)
+const (
+	MergeConditionApprovals        = "APPROVALS_REQUIRED"
+	MergeConditionChangesRequested = "CHANGES_REQUESTED"
+	MergeConditionSeniorOrOwner    = "SENIOR_OR_OWNER_APPROVAL_REQUIRED"
+)
+
+// Что делать с назначением, просроченным по SLA
+const (
+	SLAActionFlag     = "FLAG"     // только отметить нарушение
+	SLAActionReassign = "REASSIGN" // заменить ревьювера, как ReassignReviewer; замены нет — только отметить
+)
+
+// Чем закончилось нарушение SLA
+const (
+	SLABreachFlagged    = "FLAGGED"
+	SLABreachReassigned = "REASSIGNED"
 )
 
+// Итог ревью
+const (
+	VerdictApproved         = "APPROVED"
+	VerdictChangesRequested = "CHANGES_REQUESTED"
+	VerdictCommented        = "COMMENTED"
+)
+
+// Типы событий истории PR
+const (
+	EventCreated          = "created"
+	EventReadyForReview   = "ready_for_review"
+	EventReviewerAssigned = "reviewer_assigned"
+	EventReviewerReplaced = "reviewer_replaced"
+	EventReviewerRemoved  = "reviewer_removed"
+	EventReviewSubmitted  = "review_submitted"
+	EventMerged           = "merged"
+	EventClosed           = "closed"
+	EventReopened         = "reopened"
+	EventSLABreached      = "sla_breached"
+)
+
+// Почему ревьювера заменили или сняли
+const (
+	EventReasonReassign    = "REASSIGN"    // ручное переназначение
+	EventReasonDeactivated = "DEACTIVATED" // ревьювер деактивирован
+	EventReasonAbsent      = "ABSENT"      // у ревьювера началось отсутствие
+	EventReasonRebalance   = "REBALANCE"   // перенос нагрузки внутри команды
+	EventReasonSLABreach   = "SLA_BREACH"  // ревьювер не уложился в SLA команды
+	EventReasonDeclined    = "DECLINED"    // ревьювер сам отказался; причина отказа — в declines PR
+)
+
+// EventActorSystem — событие вызвано самим сервисом, а не пользователем
+const EventActorSystem = "system"
+
 type Repository interface {
 	// Teams
 	CreateTeam(teamName string, members []TeamMember) (Team, error)
 	GetTeam(teamName string) (Team, error)
+	GetTeamSettings(teamName string) (TeamSettings, error)
+	UpdateTeamSettings(teamName string, upd TeamSettingsUpdate) (TeamSettings, error)
+	GetTeamCodeOwners(teamName string) (TeamCodeOwners, error)
+	SetTeamCodeOwners(teamName, content string) (TeamCodeOwners, error)
+	RebalanceTeam(teamName string, tolerance int, dryRun bool) (RebalanceResult, error)
+
+	// Reviewer exclusions
+	AddReviewerExclusion(userID, otherUserID, reason string) (ReviewerExclusion, error)
+	RemoveReviewerExclusion(userID, otherUserID string) error
+	ListReviewerExclusions(userID string) ([]ReviewerExclusion, error)
 
 	// Users
 	SetUserIsActive(userID string, isActive bool) (User, error)
+	SetUserMaxOpenReviews(userID string, maxOpenReviews int) (User, error)
+	UpdateUser(userID string, upd UserUpdate) (User, error)
+	SetUserAbsence(userID string, start, end time.Time) (User, error)
 
 	// PR
-	CreatePullRequestWithAutoAssign(prID, prName, authorID string) (PullRequest, error)
+	CreatePullRequestWithAutoAssign(pr NewPullRequest) (PullRequest, error)
+	PreviewAssignment(pr NewPullRequest) (AssignmentPreview, error)
+	GetPullRequest(prID string) (PullRequest, error)
+	ListPullRequests(filter PullRequestFilter) (PullRequestPage, error)
 	MergePullRequest(prID string) (PullRequest, error)
-	ReassignReviewer(prID, oldUserID string) (PullRequest, string, error)
+	MarkReadyForReview(prID string) (PullRequest, error)
+	ClosePullRequest(prID string) (PullRequest, error)
+	ReopenPullRequest(prID string) (PullRequest, error)
+	ReassignReviewer(prID, oldUserID, actorID string) (PullRequest, string, error)
+	DeclineReview(prID, userID, reason string) (PullRequest, string, error)
+	SubmitReview(prID, userID, verdict string) (PullRequest, error)
 	GetUserReviews(userID string) (UserReviews, error)
+	GetPendingQueue(teamName string) ([]PendingPullRequest, error)
+	GetPullRequestHistory(prID string) ([]PREvent, error)
 
 	// Stats
 	GetStats() (Stats, error)
+	GetSLAStats(teamName string) (SLAStats, error)
 
 	// Deactivate
 	BulkDeactivateUsersAndReassign(teamName string, userIDs []string) (BulkDeactivateResult, error)
 }
 
 type TeamMember struct {
-	UserID   string
-	Username string
-	IsActive bool
+	UserID         string
+	Username       string
+	IsActive       bool
+	MaxOpenReviews *int     // 0 — без ограничения; nil — не менять (новым — 0)
+	Skills         []string // nil — не менять навыки существующего пользователя
+	Seniority      string   // junior, middle, senior; пусто — не менять (новым — DefaultSeniority)
+	// рабочие часы (пн–пт) задаются вместе; все три пустые — не менять
+	Timezone  string // IANA, например Europe/Moscow; пусто — UTC
+	WorkStart string // "HH:MM"
+	WorkEnd   string // "HH:MM"
 }
 
 type Team struct {
@@ -46,21 +221,271 @@ type Team struct {
 	Members  []TeamMember
 }
 
+type TeamSettings struct {
+	TeamName               string
+	ReviewerCount          int
+	Strategy               string // стратегия, заданная команде; пусто — из конфига
+	EffectiveStrategy      string // стратегия, которая реально применяется
+	AllowCrossTeamFallback bool
+	FallbackTeams          []string // откуда по порядку добирать ревьюверов, если своих не хватает
+	RequireSenior          bool     // среди ревьюверов PR должен быть хотя бы один senior
+	MergePolicy            MergePolicy
+	ReviewSLA              ReviewSLA
+}
+
+// MergePolicy — условия merge PR авторов команды; нулевое значение — merge без условий
+type MergePolicy struct {
+	RequiredApprovals            int  // сколько текущих ревьюверов должны одобрить PR
+	BlockOnChangesRequested      bool // нельзя, пока у кого-то из ревьюверов CHANGES_REQUESTED
+	RequireSeniorOrOwnerApproval bool // хотя бы одно одобрение от senior или владельца изменённых путей
+}
+
+// ReviewSLA — срок ревью PR авторов команды; нулевое значение — SLA нет
+type ReviewSLA struct {
+	Duration time.Duration // рабочее время ревьювера от назначения до итога ревью
+	Action   string        // SLAAction*: что делать с просроченным назначением
+}
+
+// TeamSettingsUpdate — частичное обновление настроек: nil-поля не меняются
+type TeamSettingsUpdate struct {
+	ReviewerCount          *int
+	Strategy               *string
+	AllowCrossTeamFallback *bool
+	FallbackTeams          *[]string
+	RequireSenior          *bool
+	// условия merge
+	RequiredApprovals            *int
+	BlockOnChangesRequested      *bool
+	RequireSeniorOrOwnerApproval *bool
+	// SLA ревью
+	ReviewSLA       *time.Duration
+	ReviewSLAAction *string
+}
+
+// TeamCodeOwners — правила CODEOWNERS команды
+type TeamCodeOwners struct {
+	TeamName   string
+	Content    string
+	RulesCount int
+	UpdatedAt  *time.Time
+}
+
 type User struct {
+	UserID         string
+	Username       string
+	TeamName       string
+	IsActive       bool
+	MaxOpenReviews int
+	Skills         []string
+	Seniority      string
+	Timezone       string
+	WorkStart      string
+	WorkEnd        string
+	QuietStart     string // HH:MM; в тихие часы напоминания не отправляются
+	QuietEnd       string
+	Absence        *Absence // текущее или запланированное отсутствие; nil — нет
+}
+
+// Absence — период, когда пользователь не получает ревью (отпуск, больничный)
+type Absence struct {
+	Start      time.Time
+	End        time.Time
+	HandedOver bool // отсутствие началось, открытые ревью переданы другим
+}
+
+// UserUpdate — частичное обновление пользователя: nil-поля не меняются
+type UserUpdate struct {
+	Username       *string
+	IsActive       *bool
+	MaxOpenReviews *int
+	Skills         *[]string
+	Seniority      *string
+	Timezone       *string
+	WorkStart      *string // пустая строка вместе с пустым WorkEnd снимает расписание
+	WorkEnd        *string
+	QuietStart     *string // пустая строка вместе с пустым QuietEnd снимает тихие часы
+	QuietEnd       *string
+}
+
+// NewPullRequest — данные для создания PR
+type NewPullRequest struct {
+	ID                string
+	Name              string
+	AuthorID          string
+	ChangedFiles      []string // изменённые пути; их владельцы из CODEOWNERS назначаются в первую очередь
+	Labels            []string // метки PR; предпочитаются ревьюверы с подходящими навыками
+	ExcludedReviewers []string // кого нельзя назначать на этот PR; неизвестные user_id пропускаются
+	// кого назначить явно; остальные места до reviewer_count добираются автоназначением
+	RequestedReviewers []string
+	// черновик: ревьюверы назначаются только при переводе в ready for review
+	Draft bool
+}
+
+// UnmetCondition — невыполненное условие merge
+type UnmetCondition struct {
+	Code     string   // MergeCondition*
+	Required int      // для APPROVALS_REQUIRED: сколько нужно одобрений
+	Actual   int      // для APPROVALS_REQUIRED: сколько есть
+	UserIDs  []string // для CHANGES_REQUESTED: кто запросил изменения
+}
+
+// MergeBlockedError перечисляет все невыполненные условия merge
+type MergeBlockedError struct {
+	Conditions []UnmetCondition
+}
+
+func (e *MergeBlockedError) Error() string {
+	return fmt.Sprintf("%s: %d unmet", ErrMergeBlocked, len(e.Conditions))
+}
+
+func (e *MergeBlockedError) Unwrap() error {
+	return ErrMergeBlocked
+}
+
+// InvalidReviewer — явно указанный ревьювер, которого нельзя назначить
+type InvalidReviewer struct {
+	UserID string
+	Reason string // ExclusionReason*
+}
+
+// InvalidReviewersError перечисляет всех неподходящих явно указанных ревьюверов
+type InvalidReviewersError struct {
+	Reviewers []InvalidReviewer
+}
+
+func (e *InvalidReviewersError) Error() string {
+	return fmt.Sprintf("%s: %d of them cannot be assigned", ErrInvalidReviewers, len(e.Reviewers))
+}
+
+func (e *InvalidReviewersError) Unwrap() error {
+	return ErrInvalidReviewers
+}
+
+type PullRequest struct {
+	ID                 string
+	Name               string
+	AuthorID           string
+	Status             string // DRAFT, OPEN, MERGED, CLOSED
+	Labels             []string
+	ExcludedReviewers  []string          // исключённые для этого PR при создании
+	RequestedReviewers []string          // черновик: кого назначить явно при переводе в OPEN
+	DroppedReviewers   []InvalidReviewer // перевод в OPEN: явно указанные, которых уже нельзя назначить
+	AssignedReviewers  []string
+	Reviewers          []ReviewerAssignment
+	Declines           []ReviewDecline // кто отказался от ревью; на этот PR их больше не назначают
+	PendingReviewers   int             // сколько мест ревьюверов ждут, пока у кого-то освободится лимит
+	Warnings           []string        // Warning*: не выполненные при назначении политики команды
+	CreatedAt          *time.Time
+	MergedAt           *time.Time
+	ClosedAt           *time.Time
+}
+
+// PullRequestFilter — условия ListPullRequests; пустые поля не фильтруют.
+// Диапазоны дат полуоткрытые: From включительно, To — нет.
+type PullRequestFilter struct {
+	Status                 string // DRAFT, OPEN, MERGED, CLOSED
+	AuthorID               string
+	TeamName               string // команда автора
+	ReviewerID             string // среди текущих ревьюверов
+	CreatedFrom, CreatedTo *time.Time
+	MergedFrom, MergedTo   *time.Time
+	Limit                  int    // 0 — DefaultPageSize, не больше MaxPageSize
+	Cursor                 string // NextCursor предыдущей страницы; пусто — первая страница
+}
+
+// PullRequestPage — страница ListPullRequests, новые PR первыми
+type PullRequestPage struct {
+	PullRequests []PullRequest
+	NextCursor   string // пусто — страница последняя
+}
+
+// ReviewDecline — отказ ревьювера от ревью PR
+type ReviewDecline struct {
+	UserID     string
+	Reason     string // DeclineReason*
+	ReplacedBy string // кто назначен вместо него; пусто — заменить было некем
+	DeclinedAt time.Time
+}
+
+// PREvent — запись истории PR; история только дополняется
+type PREvent struct {
+	ID            int64
+	Type          string // Event*
+	UserID        string // ревьювер, которого касается событие
+	ReplacementID string // reviewer_replaced: кто назначен вместо UserID
+	Reason        string // reviewer_assigned: источник ревьювера; reviewer_replaced/removed: EventReason*; review_submitted: итог
+	Actor         string // user_id инициатора, EventActorSystem или пусто, если неизвестен
+	CreatedAt     time.Time
+}
+
+// AssignmentPreview — кого бы назначили на PR прямо сейчас; ничего не записывается
+type AssignmentPreview struct {
+	TeamName         string
+	Strategy         string
+	SelectionSeed    int64                // сид, с которым был бы сделан выбор
+	Candidates       []PreviewCandidate   // из кого выбирали
+	Excluded         []ExcludedCandidate  // кто не попал в кандидаты и почему
+	Reviewers        []ReviewerAssignment // кого бы выбрали
+	PendingReviewers int
+	Warnings         []string
+}
+
+// PreviewCandidate — кандидат в ревьюверы с признаками, по которым его ранжируют
+type PreviewCandidate struct {
+	UserID         string
+	TeamName       string
+	CodeOwner      bool
+	MatchedLabels  []string
+	Seniority      string
+	OpenReviews    int
+	MaxOpenReviews int
+	AvailableIn    time.Duration // через сколько начнётся рабочее время; 0 — уже работает
+}
+
+// ExcludedCandidate — участник команды, которого нельзя назначить
+type ExcludedCandidate struct {
 	UserID   string
-	Username string
 	TeamName string
-	IsActive bool
+	Reason   string // ExclusionReason*
 }
 
-type PullRequest struct {
+// ReviewerExclusion — пара пользователей, которые не ревьюят PR друг друга
+type ReviewerExclusion struct {
+	UserID      string
+	OtherUserID string
+	Reason      string
+	CreatedAt   *time.Time
+}
+
+// ReviewerAssignment — назначение ревьювера с подробностями
+type ReviewerAssignment struct {
+	UserID        string
+	Source        string   // ReviewerSource*
+	TeamName      string   // команда ревьювера на момент назначения
+	MatchedLabels []string // метки PR, закрытые навыками ревьювера
+	Strategy      string   // стратегия выбора; пусто — назначен до появления записи
+	SelectionSeed *int64   // сид выбора; nil — назначен до появления записи
+	Verdict       string   // Verdict*, последний; пусто — ревьювер ещё не отозвался
+	VerdictAt     *time.Time
+	Timing        *ReviewerTiming // nil — в предпросмотре
+}
+
+// ReviewerTiming — время ревью нынешнего ревьювера; при замене ревьювера считается заново
+type ReviewerTiming struct {
+	AssignedAt    *time.Time // когда назначен; от этого момента считается SLA
+	FirstActionAt *time.Time // первый итог ревью любого вида
+	CompletedAt   *time.Time // первый APPROVED или CHANGES_REQUESTED
+}
+
+// PendingPullRequest — PR в очереди на назначение ревьюверов
+type PendingPullRequest struct {
 	ID                string
 	Name              string
 	AuthorID          string
-	Status            string
+	TeamName          string
 	AssignedReviewers []string
+	PendingReviewers  int
 	CreatedAt         *time.Time
-	MergedAt          *time.Time
 }
 
 type PullRequestShort struct {
@@ -87,9 +512,78 @@ type Stats struct {
 	AssignmentsByReviewer   []ReviewerAssignmentStat
 }
 
+// Reminder — напоминание ревьюверу об открытом PR, который ждёт его итога ревью
+type Reminder struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	UserID          string
+	Username        string
+	AssignedAt      time.Time
+	LastRemindedAt  *time.Time // nil — напоминаний по этому PR ещё не было
+}
+
+// SLABreach — назначение, по которому ревью не сделано в срок SLA команды автора
+type SLABreach struct {
+	PullRequestID string
+	UserID        string
+	TeamName      string // команда автора
+	AssignedAt    time.Time
+	DetectedAt    time.Time
+	Outcome       string // SLABreach*
+	ReplacedBy    string // REASSIGNED: кто назначен вместо UserID
+}
+
+// SLAStats — нарушения SLA, новые первыми
+type SLAStats struct {
+	TeamName   string // пусто — по всем командам
+	Total      int
+	Flagged    int
+	Reassigned int
+	ByReviewer []SLAReviewerStat
+	Breaches   []SLABreach
+}
+
+type SLAReviewerStat struct {
+	UserID   string
+	Breaches int
+}
+
+// SLARunResult — итог прохода планировщика по SLA ревью
+type SLARunResult struct {
+	Breaches []SLABreach
+}
+
 type BulkDeactivateResult struct {
 	TeamName           string
 	DeactivatedUserIDs []string
-	ReassignedCount    int // сколько раз удалось заменить ревьювера на другого
-	RemovedAssignments int // сколько ревьюверов просто удалили, потому что кандидатов не было
+	ReassignedCount    int      // сколько раз удалось заменить ревьювера на другого
+	RemovedAssignments int      // сколько ревьюверов сняли без замены; их места ушли в очередь
+	SeniorUnavailable  []string // PR, где ушёл единственный senior, а свободного senior'а нет; место ждёт senior'а
+}
+
+// AbsenceRunResult — итог прохода планировщика по отсутствиям
+type AbsenceRunResult struct {
+	StartedUserIDs     []string // у кого началось отсутствие
+	EndedUserIDs       []string // кто вернулся
+	ReassignedCount    int
+	RemovedAssignments int
+	SeniorUnavailable  []string
+}
+
+// RebalanceResult — итог выравнивания нагрузки команды
+type RebalanceResult struct {
+	TeamName     string
+	Tolerance    int  // допустимая разница открытых ревью между самым и наименее загруженным
+	DryRun       bool // только план: ничего не записано
+	SpreadBefore int
+	SpreadAfter  int
+	Moves        []RebalanceMove
+}
+
+// RebalanceMove — одно переназначение ревью с перегруженного участника на недогруженного
+type RebalanceMove struct {
+	PullRequestID string
+	FromUserID    string
+	ToUserID      string
 }
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"testing"
 	"time"
 
+	"pr-service/internal/selector"
+
 	"github.com/gavv/httpexpect/v2"
 )
 
@@ -206,3 +209,2287 @@ func TestPRService_E2E_BulkDeactivate(t *testing.T) {
 		reviewsResp.Value("pull_requests").Array().Length().IsEqual(0)
 	}
 }
+
+// третий сценарий — стратегия round_robin:
+// - команда из автора и трёх ревьюверов, одному ревьюверу на PR
+// - PR получают ревьюверов по кругу в порядке user_id
+// - переназначение продолжает тот же круг, пропуская уже назначенных
+func TestPRService_E2E_RoundRobinStrategy(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-rr-%d", suffix)
+	author := fmt.Sprintf("rr0-%d", suffix)
+	reviewers := []string{
+		fmt.Sprintf("rr1-%d", suffix),
+		fmt.Sprintf("rr2-%d", suffix),
+		fmt.Sprintf("rr3-%d", suffix),
+	}
+
+	members := []map[string]any{{"user_id": author, "username": "RRAuthor", "is_active": true}}
+	for i, id := range reviewers {
+		members = append(members, map[string]any{"user_id": id, "username": fmt.Sprintf("RRUser%d", i+1), "is_active": true})
+	}
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{"team_name": teamName, "members": members}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1, "strategy": selector.StrategyRoundRobin}).
+		Expect().
+		Status(http.StatusOK)
+
+	// четвёртый PR замыкает круг
+	for i, want := range append(reviewers, reviewers[0]) {
+		e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":   fmt.Sprintf("pr-rr-%d-%d", suffix, i),
+				"pull_request_name": fmt.Sprintf("Round robin PR %d", i),
+				"author_id":         author,
+			}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().Value("pr").Object().Value("assigned_reviewers").Array().IsEqual([]string{want})
+	}
+
+	// последним выбран rr1, на PR уже назначен он сам — следующий по кругу rr2
+	e.POST("/pullRequest/reassign").
+		WithJSON(map[string]any{
+			"pull_request_id": fmt.Sprintf("pr-rr-%d-0", suffix),
+			"old_user_id":     reviewers[0],
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("replaced_by").String().IsEqual(reviewers[1])
+}
+
+// четвёртый сценарий — стратегия least_loaded:
+// - создаём команду из автора и трёх ревьюверов со стратегией least_loaded
+// - открываем три PR, по два ревьювера на каждый
+// - проверяем, что назначения распределились поровну
+func TestPRService_E2E_LeastLoadedEvensOut(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-load-%d", suffix)
+	author := fmt.Sprintf("lu0-%d", suffix)
+	reviewers := []string{
+		fmt.Sprintf("lu1-%d", suffix),
+		fmt.Sprintf("lu2-%d", suffix),
+		fmt.Sprintf("lu3-%d", suffix),
+	}
+
+	members := []map[string]any{{"user_id": author, "username": "LoadAuthor", "is_active": true}}
+	for i, id := range reviewers {
+		members = append(members, map[string]any{"user_id": id, "username": fmt.Sprintf("LoadUser%d", i+1), "is_active": true})
+	}
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{"team_name": teamName, "members": members}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "strategy": selector.StrategyLeastLoaded}).
+		Expect().
+		Status(http.StatusOK)
+
+	for i := range 3 {
+		e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":   fmt.Sprintf("pr-load-%d-%d", suffix, i),
+				"pull_request_name": fmt.Sprintf("Load test PR %d", i),
+				"author_id":         author,
+			}).
+			Expect().
+			Status(http.StatusCreated)
+	}
+
+	for _, uid := range reviewers {
+		e.GET("/users/getReview").
+			WithQuery("user_id", uid).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object().
+			Value("pull_requests").Array().Length().IsEqual(2)
+	}
+}
+
+// пятый сценарий — число ревьюверов в настройках команды:
+// - создаём команду из автора и четырёх ревьюверов
+// - настраиваем команде трёх ревьюверов на PR
+// - проверяем, что настройка сохранилась и применяется при создании PR
+func TestPRService_E2E_TeamSettingsReviewerCount(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-settings-%d", suffix)
+	author := fmt.Sprintf("su0-%d", suffix)
+
+	members := []map[string]any{{"user_id": author, "username": "SettingsAuthor", "is_active": true}}
+	for i := 1; i <= 4; i++ {
+		members = append(members, map[string]any{
+			"user_id":   fmt.Sprintf("su%d-%d", i, suffix),
+			"username":  fmt.Sprintf("SettingsUser%d", i),
+			"is_active": true,
+		})
+	}
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{"team_name": teamName, "members": members}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.GET("/team/settings").
+		WithQuery("team_name", teamName).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("reviewer_count").Number().IsEqual(2)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 0}).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	settings := e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 3, "strategy": "round_robin"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+
+	settings.Value("reviewer_count").Number().IsEqual(3)
+	settings.Value("effective_strategy").String().IsEqual("round_robin")
+
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":   fmt.Sprintf("pr-settings-%d", suffix),
+			"pull_request_name": "Settings test PR",
+			"author_id":         author,
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().
+		Object().
+		Value("pr").Object().
+		Value("assigned_reviewers").Array().Length().IsEqual(3)
+}
+
+// шестой сценарий — лимит открытых ревью:
+// - команда из автора и одного ревьювера с лимитом в одно открытое ревью
+// - второй PR создаётся, но место ревьювера уходит в очередь
+// - после merge первого PR место из очереди занимается автоматически
+func TestPRService_E2E_CapacityPendingQueue(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-capacity-%d", suffix)
+	author := fmt.Sprintf("cu0-%d", suffix)
+	reviewer := fmt.Sprintf("cu1-%d", suffix)
+	firstPR := fmt.Sprintf("pr-capacity-%d-1", suffix)
+	secondPR := fmt.Sprintf("pr-capacity-%d-2", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "CapacityAuthor", "is_active": true},
+				{"user_id": reviewer, "username": "CapacityReviewer", "is_active": true, "max_open_reviews": 1},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1}).
+		Expect().
+		Status(http.StatusOK)
+
+	first := e.POST("/pullRequest/create").
+		WithJSON(map[string]any{"pull_request_id": firstPR, "pull_request_name": "Capacity PR 1", "author_id": author}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object()
+	first.Value("assigned_reviewers").Array().IsEqual([]string{reviewer})
+
+	second := e.POST("/pullRequest/create").
+		WithJSON(map[string]any{"pull_request_id": secondPR, "pull_request_name": "Capacity PR 2", "author_id": author}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object()
+	second.Value("assigned_reviewers").Array().IsEmpty()
+	second.Value("pending_reviewers").Number().IsEqual(1)
+
+	queue := e.GET("/pullRequest/pending").
+		WithQuery("team_name", teamName).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array()
+	queue.Length().IsEqual(1)
+	queue.Element(0).Object().Value("pull_request_id").String().IsEqual(secondPR)
+
+	e.POST("/pullRequest/merge").
+		WithJSON(map[string]any{"pull_request_id": firstPR}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/pullRequest/pending").
+		WithQuery("team_name", teamName).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array().IsEmpty()
+
+	reviews := e.GET("/users/getReview").
+		WithQuery("user_id", reviewer).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array()
+	reviews.Length().IsEqual(2)
+}
+
+// седьмой сценарий — очередь при передаче ревью:
+// - ревьювер с лимитом в одно открытое ревью уже занят, второго ревьювера деактивируют
+// - заменить некем: место ушедшего уходит в очередь, а после merge занимается
+// - повторный /team/add без max_open_reviews не сбрасывает лимит, отрицательный лимит — 400
+func TestPRService_E2E_CapacityHandOverQueue(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-handover-%d", suffix)
+	author := fmt.Sprintf("hq0-%d", suffix)
+	leaving := fmt.Sprintf("hq1-%d", suffix)
+	limited := fmt.Sprintf("hq2-%d", suffix)
+	firstPR := fmt.Sprintf("pr-handover-%d-1", suffix)
+	secondPR := fmt.Sprintf("pr-handover-%d-2", suffix)
+
+	// leaving пока неактивен, поэтому первый PR достаётся limited
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "HandOverAuthor", "is_active": true},
+				{"user_id": leaving, "username": "Leaving", "is_active": false},
+				{"user_id": limited, "username": "Limited", "is_active": true, "max_open_reviews": 1},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{"pull_request_id": firstPR, "pull_request_name": "HandOver 1", "author_id": author}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().Value("assigned_reviewers").Array().IsEqual([]string{limited})
+
+	e.POST("/users/setIsActive").
+		WithJSON(map[string]any{"user_id": leaving, "is_active": true}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{"pull_request_id": secondPR, "pull_request_name": "HandOver 2", "author_id": author}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().Value("assigned_reviewers").Array().IsEqual([]string{leaving})
+
+	e.POST("/team/deactivateUsers").
+		WithJSON(map[string]any{"team_name": teamName, "user_ids": []string{leaving}}).
+		Expect().
+		Status(http.StatusOK)
+
+	queue := e.GET("/pullRequest/pending").
+		WithQuery("team_name", teamName).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array()
+	queue.Length().IsEqual(1)
+	queue.Value(0).Object().Value("pull_request_id").IsEqual(secondPR)
+	queue.Value(0).Object().Value("assigned_reviewers").Array().IsEmpty()
+	queue.Value(0).Object().Value("pending_reviewers").IsEqual(1)
+
+	e.POST("/pullRequest/merge").
+		WithJSON(map[string]any{"pull_request_id": firstPR}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/pullRequest/pending").
+		WithQuery("team_name", teamName).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array().IsEmpty()
+
+	e.GET("/users/getReview").
+		WithQuery("user_id", limited).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array().Length().IsEqual(2)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": fmt.Sprintf("%s-moved", teamName),
+			"members": []map[string]any{
+				{"user_id": limited, "username": "Limited", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("team").Object().Value("members").Array().Value(0).Object().
+		Value("max_open_reviews").Number().IsEqual(1)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": fmt.Sprintf("%s-negative", teamName),
+			"members": []map[string]any{
+				{"user_id": limited, "username": "Limited", "is_active": true, "max_open_reviews": -1},
+			},
+		}).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+// восьмой сценарий — CODEOWNERS:
+// - команда из автора и четырёх ревьюверов, одному ревьюверу на PR
+// - загружаем CODEOWNERS, где владелец /db/ — конкретный пользователь
+// - PR с изменениями в /db/ всегда получает этого владельца
+func TestPRService_E2E_CodeOwners(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-owners-%d", suffix)
+	author := fmt.Sprintf("ou0-%d", suffix)
+	owner := fmt.Sprintf("ou3-%d", suffix)
+
+	members := []map[string]any{{"user_id": author, "username": "OwnersAuthor", "is_active": true}}
+	for i := 1; i <= 4; i++ {
+		members = append(members, map[string]any{
+			"user_id":   fmt.Sprintf("ou%d-%d", i, suffix),
+			"username":  fmt.Sprintf("OwnersUser%d", i),
+			"is_active": true,
+		})
+	}
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{"team_name": teamName, "members": members}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/team/codeowners").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"content":   fmt.Sprintf("# db ownership\n*.md @ou1-%d\n/db/ @%s\n", suffix, owner),
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("rules_count").Number().IsEqual(2)
+
+	for i := range 3 {
+		e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":   fmt.Sprintf("pr-owners-%d-%d", suffix, i),
+				"pull_request_name": "Migration",
+				"author_id":         author,
+				"changed_files":     []string{"db/migrations/001.sql"},
+			}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().Value("pr").Object().
+			Value("assigned_reviewers").Array().IsEqual([]string{owner})
+	}
+}
+
+// девятый сценарий — запасные команды:
+// - маленькая команда (автор + один ревьювер) и запасная команда platform
+// - без разрешения на fallback назначается один ревьювер
+// - после настройки цепочки второй ревьювер приходит из запасной команды и помечен как fallback
+func TestPRService_E2E_CrossTeamFallback(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	smallTeam := fmt.Sprintf("team-small-%d", suffix)
+	platformTeam := fmt.Sprintf("team-platform-%d", suffix)
+	author := fmt.Sprintf("fu0-%d", suffix)
+	teammate := fmt.Sprintf("fu1-%d", suffix)
+	platformUser := fmt.Sprintf("fp1-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": smallTeam,
+			"members": []map[string]any{
+				{"user_id": author, "username": "SmallAuthor", "is_active": true},
+				{"user_id": teammate, "username": "SmallTeammate", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": platformTeam,
+			"members": []map[string]any{
+				{"user_id": platformUser, "username": "PlatformUser", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":   fmt.Sprintf("pr-fallback-%d-1", suffix),
+			"pull_request_name": "No fallback",
+			"author_id":         author,
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().
+		Value("assigned_reviewers").Array().IsEqual([]string{teammate})
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{
+			"team_name":      smallTeam,
+			"fallback_teams": []string{smallTeam},
+		}).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{
+			"team_name":                 smallTeam,
+			"allow_cross_team_fallback": true,
+			"fallback_teams":            []string{platformTeam},
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	pr := e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":   fmt.Sprintf("pr-fallback-%d-2", suffix),
+			"pull_request_name": "With fallback",
+			"author_id":         author,
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object()
+
+	pr.Value("assigned_reviewers").Array().ContainsOnly(teammate, platformUser)
+
+	reviewers := pr.Value("reviewers").Array()
+	for i := 0; i < int(reviewers.Length().Raw()); i++ {
+		rv := reviewers.Element(i).Object()
+		if rv.Value("user_id").String().Raw() == platformUser {
+			rv.Value("source").String().IsEqual("fallback")
+			rv.Value("team_name").String().IsEqual(platformTeam)
+		} else {
+			rv.Value("source").String().IsEqual("team")
+		}
+	}
+}
+
+// десятый сценарий — навыки и метки PR:
+// - навыки пользователя хранятся в нижнем регистре без повторов
+// - PR с меткой получает ревьювера с подходящим навыком, совпавшие метки видны в reviewers
+// - обновление навыков неизвестного пользователя — 404
+func TestPRService_E2E_SkillLabels(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-skills-%d", suffix)
+	author := fmt.Sprintf("su0-%d", suffix)
+	frontend := fmt.Sprintf("su1-%d", suffix)
+	dba := fmt.Sprintf("su2-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "SkillAuthor", "is_active": true},
+				{"user_id": frontend, "username": "Frontend", "is_active": true, "skills": []string{"Frontend"}},
+				{"user_id": dba, "username": "Dba", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/users/update").
+		WithJSON(map[string]any{
+			"user_id": dba,
+			"skills":  []string{"go", "SQL", "sql"},
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("user").Object().
+		Value("skills").Array().IsEqual([]string{"go", "sql"})
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{
+			"team_name":      teamName,
+			"reviewer_count": 1,
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	for i := 0; i < 3; i++ {
+		pr := e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":   fmt.Sprintf("pr-skills-%d-%d", suffix, i),
+				"pull_request_name": "Migration",
+				"author_id":         author,
+				"labels":            []string{"sql"},
+			}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().Value("pr").Object()
+
+		pr.Value("labels").Array().IsEqual([]string{"sql"})
+		pr.Value("assigned_reviewers").Array().IsEqual([]string{dba})
+		pr.Value("reviewers").Array().Element(0).Object().
+			Value("matched_labels").Array().IsEqual([]string{"sql"})
+	}
+
+	e.POST("/users/update").
+		WithJSON(map[string]any{
+			"user_id": "no-such-user",
+			"skills":  []string{"go"},
+		}).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+// одиннадцатый сценарий — требование senior:
+// - при require_senior среди ревьюверов всегда есть senior
+// - единственного senior нельзя заменить не-senior'ом, при деактивации его место ждёт в очереди
+// - если в команде нет senior, PR создаётся с предупреждением
+func TestPRService_E2E_RequireSenior(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-senior-%d", suffix)
+	juniorTeam := fmt.Sprintf("team-juniors-%d", suffix)
+	author := fmt.Sprintf("ru0-%d", suffix)
+	senior := fmt.Sprintf("ru1-%d", suffix)
+	junior1 := fmt.Sprintf("ru2-%d", suffix)
+	junior2 := fmt.Sprintf("ru3-%d", suffix)
+	juniorAuthor := fmt.Sprintf("rj0-%d", suffix)
+	juniorMate := fmt.Sprintf("rj1-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "SeniorAuthor", "is_active": true, "seniority": "junior"},
+				{"user_id": senior, "username": "Senior", "is_active": true, "seniority": "senior"},
+				{"user_id": junior1, "username": "Junior1", "is_active": true, "seniority": "junior"},
+				{"user_id": junior2, "username": "Junior2", "is_active": true, "seniority": "junior"},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": juniorTeam,
+			"members": []map[string]any{
+				{"user_id": juniorAuthor, "username": "JuniorAuthor", "is_active": true, "seniority": "junior"},
+				{"user_id": juniorMate, "username": "JuniorMate", "is_active": true, "seniority": "lead"},
+			},
+		}).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": juniorTeam,
+			"members": []map[string]any{
+				{"user_id": juniorAuthor, "username": "JuniorAuthor", "is_active": true, "seniority": "junior"},
+				{"user_id": juniorMate, "username": "JuniorMate", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	for _, team := range []string{teamName, juniorTeam} {
+		e.POST("/team/settings").
+			WithJSON(map[string]any{
+				"team_name":      team,
+				"require_senior": true,
+			}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("require_senior").Boolean().IsTrue()
+	}
+
+	// senior всегда среди двух ревьюверов, хотя джунов больше
+	for i := 0; i < 3; i++ {
+		e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":   fmt.Sprintf("pr-senior-%d-%d", suffix, i),
+				"pull_request_name": "Critical",
+				"author_id":         author,
+			}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().Value("pr").Object().
+			Value("assigned_reviewers").Array().ContainsAll(senior)
+	}
+
+	// единственного senior заменить некем
+	e.POST("/pullRequest/reassign").
+		WithJSON(map[string]any{
+			"pull_request_id": fmt.Sprintf("pr-senior-%d-0", suffix),
+			"old_user_id":     senior,
+		}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("error").Object().
+		Value("code").String().IsEqual("NO_SENIOR_CANDIDATE")
+
+	// при деактивации senior'а джуны его место не занимают — оно ждёт senior'а в очереди
+	prIDs := []string{
+		fmt.Sprintf("pr-senior-%d-0", suffix),
+		fmt.Sprintf("pr-senior-%d-1", suffix),
+		fmt.Sprintf("pr-senior-%d-2", suffix),
+	}
+	deact := e.POST("/team/deactivateUsers").
+		WithJSON(map[string]any{"team_name": teamName, "user_ids": []string{senior}}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	deact.Value("removed_reviewers").Number().IsEqual(3)
+	deact.Value("senior_unavailable").Array().ContainsOnly(prIDs[0], prIDs[1], prIDs[2])
+
+	queue := e.GET("/pullRequest/pending").
+		WithQuery("team_name", teamName).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array()
+	queue.Length().IsEqual(len(prIDs))
+	for i := range prIDs {
+		queued := queue.Element(i).Object()
+		queued.Value("assigned_reviewers").Array().Length().IsEqual(1)
+		queued.Value("pending_reviewers").Number().IsEqual(1)
+	}
+
+	e.POST("/users/setIsActive").
+		WithJSON(map[string]any{"user_id": senior, "is_active": true}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/pullRequest/pending").
+		WithQuery("team_name", teamName).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array().Length().IsEqual(0)
+
+	reviews := e.GET("/users/getReview").
+		WithQuery("user_id", senior).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array()
+	reviews.Length().IsEqual(len(prIDs))
+
+	// в команде нет senior — PR создаётся, но с предупреждением
+	pr := e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":   fmt.Sprintf("pr-senior-%d-juniors", suffix),
+			"pull_request_name": "Juniors only",
+			"author_id":         juniorAuthor,
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object()
+
+	pr.Value("assigned_reviewers").Array().IsEqual([]string{juniorMate})
+	pr.Value("policy_warnings").Array().IsEqual([]string{"SENIOR_REVIEWER_UNAVAILABLE"})
+
+	e.POST("/users/update").
+		WithJSON(map[string]any{
+			"user_id":   juniorMate,
+			"seniority": "senior",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("user").Object().
+		Value("seniority").String().IsEqual("senior")
+}
+
+// двенадцатый сценарий — повтор выбора по сиду:
+// - по сохранённому сиду и стратегии выбор повторяется вне сервиса
+func TestPRService_E2E_SelectionSeedReplay(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-seed-%d", suffix)
+	author := fmt.Sprintf("du0-%d", suffix)
+	members := []string{
+		fmt.Sprintf("du1-%d", suffix),
+		fmt.Sprintf("du2-%d", suffix),
+		fmt.Sprintf("du3-%d", suffix),
+		fmt.Sprintf("du4-%d", suffix),
+	}
+
+	payload := []map[string]any{{"user_id": author, "username": "SeedAuthor", "is_active": true}}
+	for i, m := range members {
+		payload = append(payload, map[string]any{"user_id": m, "username": fmt.Sprintf("Seed%d", i), "is_active": true})
+	}
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{"team_name": teamName, "members": payload}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "strategy": selector.StrategyRandom}).
+		Expect().
+		Status(http.StatusOK)
+
+	for i := 0; i < 3; i++ {
+		pr := e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":   fmt.Sprintf("pr-seed-%d-%d", suffix, i),
+				"pull_request_name": "Seeded",
+				"author_id":         author,
+			}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().Value("pr").Object()
+
+		first := pr.Value("reviewers").Array().Element(0).Object()
+		first.Value("strategy").String().IsEqual(selector.StrategyRandom)
+		seed, err := strconv.ParseInt(first.Value("selection_seed").String().Raw(), 10, 64)
+		if err != nil {
+			t.Fatalf("selection_seed: %v", err)
+		}
+
+		candidates := make([]selector.Candidate, 0, len(members))
+		for _, m := range members {
+			candidates = append(candidates, selector.Candidate{UserID: m})
+		}
+		replayed := selector.Random{}.Select(selector.NewRand(seed), teamName, candidates, 2)
+
+		want := make([]string, 0, len(replayed))
+		for _, c := range replayed {
+			want = append(want, c.UserID)
+		}
+		pr.Value("assigned_reviewers").Array().ContainsOnly(toAny(want)...)
+	}
+}
+
+func toAny(ss []string) []any {
+	out := make([]any, 0, len(ss))
+	for _, s := range ss {
+		out = append(out, s)
+	}
+	return out
+}
+
+// тринадцатый сценарий — исключения ревьюверов:
+// - пара исключения симметрична и не может состоять из одного пользователя
+// - PR не получает ни ревьювера из пары с автором, ни исключённых для этого PR
+// - после снятия пары пользователь снова доступен для замены
+func TestPRService_E2E_ReviewerExclusions(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-excl-%d", suffix)
+	author := fmt.Sprintf("xu0-%d", suffix)
+	manager := fmt.Sprintf("xu1-%d", suffix)
+	cofounder := fmt.Sprintf("xu2-%d", suffix)
+	other := fmt.Sprintf("xu3-%d", suffix)
+	prID := fmt.Sprintf("pr-excl-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "ExclAuthor", "is_active": true},
+				{"user_id": manager, "username": "Manager", "is_active": true},
+				{"user_id": cofounder, "username": "Cofounder", "is_active": true},
+				{"user_id": other, "username": "Other", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/exclusions/add").
+		WithJSON(map[string]any{"user_id": author, "other_user_id": author}).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	// пара симметрична: порядок пользователей не важен
+	e.POST("/exclusions/add").
+		WithJSON(map[string]any{"user_id": manager, "other_user_id": author, "reason": "manager/report"}).
+		Expect().
+		Status(http.StatusCreated)
+
+	list := e.GET("/exclusions/list").
+		WithQuery("user_id", author).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("exclusions").Array()
+	list.Length().IsEqual(1)
+	list.Element(0).Object().Value("other_user_id").String().IsEqual(manager)
+	list.Element(0).Object().Value("reason").String().IsEqual("manager/report")
+
+	pr := e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":    prID,
+			"pull_request_name":  "Excluded",
+			"author_id":          author,
+			"excluded_reviewers": []string{cofounder},
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object()
+
+	pr.Value("assigned_reviewers").Array().IsEqual([]string{other})
+	pr.Value("excluded_reviewers").Array().IsEqual([]string{cofounder})
+
+	// заменить некем: один в паре с автором, другой исключён для PR
+	e.POST("/pullRequest/reassign").
+		WithJSON(map[string]any{"pull_request_id": prID, "old_user_id": other}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("error").Object().
+		Value("code").String().IsEqual("NO_CANDIDATE")
+
+	e.POST("/exclusions/remove").
+		WithJSON(map[string]any{"user_id": author, "other_user_id": manager}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/exclusions/remove").
+		WithJSON(map[string]any{"user_id": author, "other_user_id": manager}).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.POST("/pullRequest/reassign").
+		WithJSON(map[string]any{"pull_request_id": prID, "old_user_id": other}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("replaced_by").String().IsEqual(manager)
+}
+
+// четырнадцатый сценарий — рабочие часы:
+// - ревьювер вне своего рабочего окна не назначается, пока есть кто-то в рабочее время
+// - рабочее окно не может переходить через полночь
+func TestPRService_E2E_WorkingHours(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-hours-%d", suffix)
+	author := fmt.Sprintf("hu0-%d", suffix)
+	sleeping := fmt.Sprintf("hu1-%d", suffix)
+	awake := fmt.Sprintf("hu2-%d", suffix)
+
+	// окно, в которое текущий час точно не попадает
+	start := (time.Now().UTC().Hour() + 2) % 24
+	workStart := fmt.Sprintf("%02d:00", start)
+	workEnd := fmt.Sprintf("%02d:59", start)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "HoursAuthor", "is_active": true},
+				{"user_id": sleeping, "username": "Sleeping", "is_active": true,
+					"timezone": "UTC", "work_start": workStart, "work_end": workEnd},
+				{"user_id": awake, "username": "Awake", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1, "strategy": selector.StrategyRandom}).
+		Expect().
+		Status(http.StatusOK)
+
+	for i := 0; i < 3; i++ {
+		e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":   fmt.Sprintf("pr-hours-%d-%d", suffix, i),
+				"pull_request_name": "Evening PR",
+				"author_id":         author,
+			}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().Value("pr").Object().
+			Value("assigned_reviewers").Array().IsEqual([]string{awake})
+	}
+
+	e.POST("/users/update").
+		WithJSON(map[string]any{"user_id": awake, "timezone": "Asia/Novosibirsk", "work_start": "18:00", "work_end": "09:00"}).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object().
+		Value("code").String().IsEqual("INVALID_WORK_HOURS")
+
+	e.POST("/users/update").
+		WithJSON(map[string]any{"user_id": awake, "timezone": "Asia/Novosibirsk", "work_start": "09:00", "work_end": "18:00"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("user").Object().
+		Value("timezone").String().IsEqual("Asia/Novosibirsk")
+}
+
+// пятнадцатый сценарий — отсутствие:
+// - пока пользователь отсутствует, его ревью переданы другим, новые PR его не получают
+// - после конца периода он снова назначается
+func TestPRService_E2E_Absence(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-absence-%d", suffix)
+	author := fmt.Sprintf("ab0-%d", suffix)
+	away := fmt.Sprintf("ab1-%d", suffix)
+	other := fmt.Sprintf("ab2-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "AbsenceAuthor", "is_active": true},
+				{"user_id": away, "username": "Away", "is_active": true},
+				{"user_id": other, "username": "Other", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1}).
+		Expect().
+		Status(http.StatusOK)
+
+	createExcludingOther := func(prID string) *httpexpect.Object {
+		return e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":    prID,
+				"pull_request_name":  "Absence PR",
+				"author_id":          author,
+				"excluded_reviewers": []string{other},
+			}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().Value("pr").Object()
+	}
+
+	// первый PR достаётся away: other пока выключен
+	setActive := func(userID string, active bool) {
+		e.POST("/users/setIsActive").
+			WithJSON(map[string]any{"user_id": userID, "is_active": active}).
+			Expect().
+			Status(http.StatusOK)
+	}
+	setActive(other, false)
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":   fmt.Sprintf("pr-absence-%d-0", suffix),
+			"pull_request_name": "Absence PR",
+			"author_id":         author,
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().
+		Value("assigned_reviewers").Array().IsEqual([]string{away})
+	setActive(other, true)
+
+	e.POST("/users/setAbsence").
+		WithJSON(map[string]any{"user_id": away, "start": time.Now().Add(time.Hour), "end": time.Now()}).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object().
+		Value("code").String().IsEqual("INVALID_ABSENCE")
+
+	// отсутствие уже началось — ревью передаются сразу
+	absence := e.POST("/users/setAbsence").
+		WithJSON(map[string]any{"user_id": away, "start": time.Now().Add(-time.Hour), "end": time.Now().Add(3 * time.Second)}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("user").Object().Value("absence").Object()
+	absence.Value("handed_over").Boolean().IsTrue()
+
+	e.GET("/users/getReview").
+		WithQuery("user_id", away).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array().Length().IsEqual(0)
+
+	e.GET("/users/getReview").
+		WithQuery("user_id", other).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array().Length().IsEqual(1)
+
+	createExcludingOther(fmt.Sprintf("pr-absence-%d-1", suffix)).
+		Value("assigned_reviewers").Array().IsEmpty()
+
+	time.Sleep(4 * time.Second)
+
+	createExcludingOther(fmt.Sprintf("pr-absence-%d-2", suffix)).
+		Value("assigned_reviewers").Array().IsEqual([]string{away})
+}
+
+// шестнадцатый сценарий — пробный выбор:
+// - показывает пул, исключённых с причинами и кого бы назначили
+// - ничего не записывает и не сдвигает очередь round_robin: create выбирает того же
+func TestPRService_E2E_PreviewAssignment(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-preview-%d", suffix)
+	author := fmt.Sprintf("pv0-%d", suffix)
+	idle := fmt.Sprintf("pv1-%d", suffix)
+	busy := fmt.Sprintf("pv2-%d", suffix)
+	ok1 := fmt.Sprintf("pv3-%d", suffix)
+	ok2 := fmt.Sprintf("pv4-%d", suffix)
+	skipped := fmt.Sprintf("pv5-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "PreviewAuthor", "is_active": true},
+				{"user_id": idle, "username": "Idle", "is_active": false},
+				{"user_id": busy, "username": "Busy", "is_active": true, "max_open_reviews": 1},
+				{"user_id": ok1, "username": "Ok1", "is_active": true},
+				{"user_id": ok2, "username": "Ok2", "is_active": true},
+				{"user_id": skipped, "username": "Skipped", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1, "strategy": selector.StrategyRoundRobin}).
+		Expect().
+		Status(http.StatusOK)
+
+	// busy упирается в лимит
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":    fmt.Sprintf("pr-preview-%d-0", suffix),
+			"pull_request_name":  "Fill busy",
+			"author_id":          author,
+			"excluded_reviewers": []string{ok1, ok2, skipped},
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().
+		Value("assigned_reviewers").Array().IsEqual([]string{busy})
+
+	params := map[string]any{"author_id": author, "excluded_reviewers": []string{skipped}}
+
+	preview := e.POST("/pullRequest/previewAssignment").
+		WithJSON(params).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("preview").Object()
+
+	preview.Value("strategy").String().IsEqual(selector.StrategyRoundRobin)
+	preview.Value("candidates").Array().Length().IsEqual(2)
+	preview.Value("excluded").Array().ContainsOnly(
+		map[string]any{"user_id": author, "team_name": teamName, "reason": "AUTHOR"},
+		map[string]any{"user_id": idle, "team_name": teamName, "reason": "INACTIVE"},
+		map[string]any{"user_id": busy, "team_name": teamName, "reason": "AT_CAPACITY"},
+		map[string]any{"user_id": skipped, "team_name": teamName, "reason": "EXCLUDED_FOR_PR"},
+	)
+
+	reviewers := preview.Value("reviewers").Array()
+	reviewers.Length().IsEqual(1)
+	picked := reviewers.Value(0).Object().Value("user_id").String().Raw()
+
+	// повторный пробный выбор даёт то же самое
+	e.POST("/pullRequest/previewAssignment").
+		WithJSON(params).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("preview").Object().
+		Value("reviewers").Array().Value(0).Object().
+		Value("user_id").String().IsEqual(picked)
+
+	params["pull_request_id"] = fmt.Sprintf("pr-preview-%d-1", suffix)
+	params["pull_request_name"] = "Previewed"
+	e.POST("/pullRequest/create").
+		WithJSON(params).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().
+		Value("assigned_reviewers").Array().IsEqual([]string{picked})
+
+	e.POST("/pullRequest/previewAssignment").
+		WithJSON(map[string]any{"author_id": fmt.Sprintf("nobody-%d", suffix)}).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+// семнадцатый сценарий — выравнивание нагрузки:
+// - dry_run возвращает план и ничего не меняет
+// - настоящий прогон делает те же переносы
+func TestPRService_E2E_TeamRebalance(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-rebalance-%d", suffix)
+	author := fmt.Sprintf("rb0-%d", suffix)
+	loaded := fmt.Sprintf("rb1-%d", suffix)
+	idle1 := fmt.Sprintf("rb2-%d", suffix)
+	idle2 := fmt.Sprintf("rb3-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "RebalanceAuthor", "is_active": true},
+				{"user_id": loaded, "username": "Loaded", "is_active": true},
+				{"user_id": idle1, "username": "Idle1", "is_active": false},
+				{"user_id": idle2, "username": "Idle2", "is_active": false},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1}).
+		Expect().
+		Status(http.StatusOK)
+
+	// все ревью достаются loaded, пока остальные выключены
+	for i := range 4 {
+		e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":   fmt.Sprintf("pr-rebalance-%d-%d", suffix, i),
+				"pull_request_name": "Rebalance PR",
+				"author_id":         author,
+			}).
+			Expect().
+			Status(http.StatusCreated)
+	}
+	for _, uid := range []string{idle1, idle2} {
+		e.POST("/users/setIsActive").
+			WithJSON(map[string]any{"user_id": uid, "is_active": true}).
+			Expect().
+			Status(http.StatusOK)
+	}
+
+	reviewsOf := func(userID string) *httpexpect.Array {
+		return e.GET("/users/getReview").
+			WithQuery("user_id", userID).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("pull_requests").Array()
+	}
+
+	plan := e.POST("/team/rebalance").
+		WithJSON(map[string]any{"team_name": teamName, "tolerance": 1, "dry_run": true}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+
+	plan.Value("dry_run").Boolean().IsTrue()
+	plan.Value("spread_before").Number().IsEqual(4)
+	// автор не может ревьюить свои PR, поэтому ниже 2 перекос не опустить
+	plan.Value("spread_after").Number().IsEqual(2)
+	plan.Value("moves").Array().Length().IsEqual(2)
+	reviewsOf(loaded).Length().IsEqual(4)
+
+	done := e.POST("/team/rebalance").
+		WithJSON(map[string]any{"team_name": teamName, "tolerance": 1}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+
+	done.Value("dry_run").Boolean().IsFalse()
+	done.Value("moves").IsEqual(plan.Value("moves").Raw())
+	reviewsOf(loaded).Length().IsEqual(2)
+	reviewsOf(idle1).Length().IsEqual(1)
+	reviewsOf(idle2).Length().IsEqual(1)
+
+	e.POST("/team/rebalance").
+		WithJSON(map[string]any{"team_name": teamName, "tolerance": -1}).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+// восемнадцатый сценарий — стратегия sticky:
+// - ревьювер смёрженного PR автора получает его следующие PR, даже если он загружен сильнее
+// - на PR другого автора привязанность не переносится
+func TestPRService_E2E_StickyStrategy(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-sticky-%d", suffix)
+	author := fmt.Sprintf("st0-%d", suffix)
+	other := fmt.Sprintf("st1-%d", suffix)
+	regular := fmt.Sprintf("st2-%d", suffix)
+	third := fmt.Sprintf("st3-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "StickyAuthor", "is_active": true},
+				{"user_id": other, "username": "OtherAuthor", "is_active": true},
+				{"user_id": regular, "username": "Regular", "is_active": true},
+				{"user_id": third, "username": "Third", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1, "strategy": selector.StrategySticky}).
+		Expect().
+		Status(http.StatusOK)
+
+	firstPR := fmt.Sprintf("pr-sticky-%d-0", suffix)
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":    firstPR,
+			"pull_request_name":  "First",
+			"author_id":          author,
+			"excluded_reviewers": []string{other, third},
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().
+		Value("assigned_reviewers").Array().IsEqual([]string{regular})
+
+	e.POST("/pullRequest/merge").
+		WithJSON(map[string]any{"pull_request_id": firstPR}).
+		Expect().
+		Status(http.StatusOK)
+
+	for i := 1; i <= 2; i++ {
+		e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":   fmt.Sprintf("pr-sticky-%d-%d", suffix, i),
+				"pull_request_name": "Follow-up",
+				"author_id":         author,
+			}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().Value("pr").Object().
+			Value("assigned_reviewers").Array().IsEqual([]string{regular})
+	}
+
+	// у другого автора истории нет: выбирается наименее загруженный
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":   fmt.Sprintf("pr-sticky-%d-3", suffix),
+			"pull_request_name": "Other author",
+			"author_id":         other,
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().
+		Value("assigned_reviewers").Array().NotContainsAll(regular)
+}
+
+// девятнадцатый сценарий — явно указанные ревьюверы:
+// - все неподходящие перечислены с причинами, PR не создаётся
+// - подходящие назначаются первыми, оставшиеся места добираются автоматически
+func TestPRService_E2E_RequestedReviewers(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-req-%d", suffix)
+	otherTeam := fmt.Sprintf("team-req-other-%d", suffix)
+	author := fmt.Sprintf("rq0-%d", suffix)
+	wanted := fmt.Sprintf("rq1-%d", suffix)
+	inactive := fmt.Sprintf("rq2-%d", suffix)
+	filler := fmt.Sprintf("rq3-%d", suffix)
+	outsider := fmt.Sprintf("rq4-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "ReqAuthor", "is_active": true},
+				{"user_id": wanted, "username": "Wanted", "is_active": true},
+				{"user_id": inactive, "username": "Inactive", "is_active": false},
+				{"user_id": filler, "username": "Filler", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": otherTeam,
+			"members": []map[string]any{
+				{"user_id": outsider, "username": "Outsider", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	prID := fmt.Sprintf("pr-req-%d", suffix)
+
+	// все неподходящие перечислены с причинами, PR не создаётся
+	errObj := e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":     prID,
+			"pull_request_name":   "Requested",
+			"author_id":           author,
+			"requested_reviewers": []string{wanted, author, inactive, outsider, "ghost-" + prID},
+		}).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object()
+	errObj.Value("code").IsEqual("INVALID_REQUESTED_REVIEWERS")
+	errObj.Value("reviewers").Array().IsEqual([]map[string]any{
+		{"user_id": author, "reason": "AUTHOR"},
+		{"user_id": inactive, "reason": "INACTIVE"},
+		{"user_id": outsider, "reason": "NOT_TEAM_MEMBER"},
+		{"user_id": "ghost-" + prID, "reason": "NOT_FOUND"},
+	})
+
+	// указанный назначается первым, второе место добирается автоматически
+	pr := e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":     prID,
+			"pull_request_name":   "Requested",
+			"author_id":           author,
+			"requested_reviewers": []string{wanted},
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object()
+	pr.Value("assigned_reviewers").Array().IsEqual([]string{wanted, filler})
+
+	reviewers := pr.Value("reviewers").Array()
+	reviewers.Value(0).Object().Value("source").IsEqual("requested")
+	reviewers.Value(0).Object().NotContainsKey("selection_seed")
+	reviewers.Value(1).Object().Value("source").IsEqual("team")
+}
+
+// двадцатый сценарий — отказ от ревью:
+// - причина отказа — только из известных значений
+// - отказавшегося заменяют, отказ с причиной виден в PR
+// - отказавшегося не вернуть на этот PR; без замены отказ принимается, а место ждёт в очереди
+func TestPRService_E2E_DeclineReview(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-decline-%d", suffix)
+	author := fmt.Sprintf("dc0-%d", suffix)
+	first := fmt.Sprintf("dc1-%d", suffix)
+	second := fmt.Sprintf("dc2-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "DeclineAuthor", "is_active": true},
+				{"user_id": first, "username": "First", "is_active": true},
+				{"user_id": second, "username": "Second", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1}).
+		Expect().
+		Status(http.StatusOK)
+
+	prID := fmt.Sprintf("pr-decline-%d", suffix)
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":     prID,
+			"pull_request_name":   "Decline",
+			"author_id":           author,
+			"requested_reviewers": []string{first},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/pullRequest/decline").
+		WithJSON(map[string]any{"pull_request_id": prID, "user_id": first, "reason": "BORED"}).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object().Value("code").IsEqual("INVALID_REASON")
+
+	res := e.POST("/pullRequest/decline").
+		WithJSON(map[string]any{"pull_request_id": prID, "user_id": first, "reason": "NO_EXPERTISE"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	res.Value("replaced_by").IsEqual(second)
+
+	pr := res.Value("pr").Object()
+	pr.Value("assigned_reviewers").Array().IsEqual([]string{second})
+	decline := pr.Value("declines").Array().Value(0).Object()
+	decline.Value("user_id").IsEqual(first)
+	decline.Value("reason").IsEqual("NO_EXPERTISE")
+	decline.Value("replaced_by").IsEqual(second)
+
+	// отказавшегося не вернуть на этот PR: замены для второго нет
+	e.POST("/pullRequest/reassign").
+		WithJSON(map[string]any{"pull_request_id": prID, "old_user_id": second}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("error").Object().Value("code").IsEqual("NO_CANDIDATE")
+
+	// отказ принимается и без замены: место ждёт в очереди
+	queued := e.POST("/pullRequest/decline").
+		WithJSON(map[string]any{"pull_request_id": prID, "user_id": second, "reason": "OVERLOADED"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	queued.NotContainsKey("replaced_by")
+	pr = queued.Value("pr").Object()
+	pr.Value("assigned_reviewers").Array().IsEmpty()
+	pr.Value("pending_reviewers").IsEqual(1)
+	pr.Value("declines").Array().Length().IsEqual(2)
+	pr.Value("declines").Array().Value(1).Object().NotContainsKey("replaced_by")
+}
+
+// двадцать первый сценарий — жизненный цикл PR:
+// - черновик без ревьюверов: merge и reopen запрещены
+// - закрытый черновик после reopen снова черновик, ready назначает указанных при создании
+// - закрытый PR нельзя смёржить или переназначить, reopen возвращает его ревьюверов
+// - смёрженный PR не закрыть и не открыть заново
+func TestPRService_E2E_PRLifecycle(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-lifecycle-%d", suffix)
+	author := fmt.Sprintf("lc0-%d", suffix)
+	wanted := fmt.Sprintf("lc1-%d", suffix)
+	other := fmt.Sprintf("lc2-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "LifecycleAuthor", "is_active": true},
+				{"user_id": wanted, "username": "Wanted", "is_active": true},
+				{"user_id": other, "username": "Other", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1}).
+		Expect().
+		Status(http.StatusOK)
+
+	prID := fmt.Sprintf("pr-lifecycle-%d", suffix)
+	statusCall := func(path string, status int) *httpexpect.Object {
+		return e.POST(path).
+			WithJSON(map[string]any{"pull_request_id": prID}).
+			Expect().
+			Status(status).
+			JSON().Object()
+	}
+	errorCode := func(path, code string) {
+		statusCall(path, http.StatusConflict).Value("error").Object().Value("code").IsEqual(code)
+	}
+
+	// черновик: без ревьюверов, ничего не смёржить и не переназначить
+	draft := e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":     prID,
+			"pull_request_name":   "Lifecycle",
+			"author_id":           author,
+			"requested_reviewers": []string{wanted},
+			"draft":               true,
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object()
+	draft.Value("status").IsEqual("DRAFT")
+	draft.Value("assigned_reviewers").Array().IsEmpty()
+	draft.Value("requested_reviewers").Array().IsEqual([]string{wanted})
+
+	errorCode("/pullRequest/merge", "PR_DRAFT")
+	errorCode("/pullRequest/reopen", "INVALID_TRANSITION")
+
+	// закрытый черновик возвращается черновиком
+	statusCall("/pullRequest/close", http.StatusOK).Value("pr").Object().Value("status").IsEqual("CLOSED")
+	statusCall("/pullRequest/close", http.StatusOK).Value("pr").Object().Value("status").IsEqual("CLOSED")
+	errorCode("/pullRequest/ready", "PR_CLOSED")
+	statusCall("/pullRequest/reopen", http.StatusOK).Value("pr").Object().Value("status").IsEqual("DRAFT")
+
+	// ready назначает указанного при создании
+	ready := statusCall("/pullRequest/ready", http.StatusOK).Value("pr").Object()
+	ready.Value("status").IsEqual("OPEN")
+	ready.Value("assigned_reviewers").Array().IsEqual([]string{wanted})
+	ready.Value("reviewers").Array().Value(0).Object().Value("source").IsEqual("requested")
+	ready.NotContainsKey("requested_reviewers")
+	ready.NotContainsKey("policy_warnings")
+	errorCode("/pullRequest/ready", "INVALID_TRANSITION")
+
+	// закрытый PR не занимает ревьювера и не даёт его переназначить
+	closed := statusCall("/pullRequest/close", http.StatusOK).Value("pr").Object()
+	closed.Value("status").IsEqual("CLOSED")
+	closed.ContainsKey("closedAt")
+	errorCode("/pullRequest/merge", "PR_CLOSED")
+	e.POST("/pullRequest/reassign").
+		WithJSON(map[string]any{"pull_request_id": prID, "old_user_id": wanted}).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().Value("error").Object().Value("code").IsEqual("PR_CLOSED")
+
+	reopened := statusCall("/pullRequest/reopen", http.StatusOK).Value("pr").Object()
+	reopened.Value("status").IsEqual("OPEN")
+	reopened.NotContainsKey("closedAt")
+	reopened.Value("assigned_reviewers").Array().IsEqual([]string{wanted})
+
+	statusCall("/pullRequest/merge", http.StatusOK).Value("pr").Object().Value("status").IsEqual("MERGED")
+	errorCode("/pullRequest/close", "PR_MERGED")
+	errorCode("/pullRequest/reopen", "PR_MERGED")
+}
+
+// двадцать второй сценарий — черновик с недоступным указанным ревьювером:
+// - указанного в черновике ревьювера деактивируют до перехода в OPEN
+// - ready всё равно переводит PR: место добирается автоназначением
+// - пропущенный ревьювер возвращается с причиной и предупреждением
+func TestPRService_E2E_ReadyDropsUnavailableRequested(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-ready-%d", suffix)
+	author := fmt.Sprintf("rd0-%d", suffix)
+	gone := fmt.Sprintf("rd1-%d", suffix)
+	other := fmt.Sprintf("rd2-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "ReadyAuthor", "is_active": true},
+				{"user_id": gone, "username": "Gone", "is_active": true},
+				{"user_id": other, "username": "Other", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1}).
+		Expect().
+		Status(http.StatusOK)
+
+	prID := fmt.Sprintf("pr-ready-%d", suffix)
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":     prID,
+			"pull_request_name":   "Ready",
+			"author_id":           author,
+			"requested_reviewers": []string{gone},
+			"draft":               true,
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().Value("requested_reviewers").Array().IsEqual([]string{gone})
+
+	e.POST("/users/setIsActive").
+		WithJSON(map[string]any{"user_id": gone, "is_active": false}).
+		Expect().
+		Status(http.StatusOK)
+
+	ready := e.POST("/pullRequest/ready").
+		WithJSON(map[string]any{"pull_request_id": prID}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pr").Object()
+	ready.Value("status").IsEqual("OPEN")
+	ready.Value("assigned_reviewers").Array().IsEqual([]string{other})
+	ready.Value("policy_warnings").Array().IsEqual([]string{"REQUESTED_REVIEWER_UNAVAILABLE"})
+	dropped := ready.Value("dropped_requested_reviewers").Array()
+	dropped.Length().IsEqual(1)
+	dropped.Value(0).Object().Value("user_id").IsEqual(gone)
+	dropped.Value(0).Object().Value("reason").IsEqual("INACTIVE")
+}
+
+// двадцать третий сценарий — итоги ревью:
+// - итог ставит только назначенный ревьювер и только из известных значений
+// - последний итог ревьювера заменяет прежний
+// - при замене ревьювера его итог не переходит к новому
+// - после merge итог больше не принимается
+func TestPRService_E2E_ReviewVerdicts(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-verdict-%d", suffix)
+	author := fmt.Sprintf("vd0-%d", suffix)
+	first := fmt.Sprintf("vd1-%d", suffix)
+	second := fmt.Sprintf("vd2-%d", suffix)
+	spare := fmt.Sprintf("vd3-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "VerdictAuthor", "is_active": true},
+				{"user_id": first, "username": "First", "is_active": true},
+				{"user_id": second, "username": "Second", "is_active": true},
+				{"user_id": spare, "username": "Spare", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	prID := fmt.Sprintf("pr-verdict-%d", suffix)
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":     prID,
+			"pull_request_name":   "Verdicts",
+			"author_id":           author,
+			"requested_reviewers": []string{first, second},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	review := func(userID, verdict string, status int) *httpexpect.Object {
+		return e.POST("/pullRequest/review").
+			WithJSON(map[string]any{"pull_request_id": prID, "user_id": userID, "verdict": verdict}).
+			Expect().
+			Status(status).
+			JSON().Object()
+	}
+
+	review(first, "LGTM", http.StatusBadRequest).Value("error").Object().Value("code").IsEqual("INVALID_VERDICT")
+	review(spare, "APPROVED", http.StatusConflict).Value("error").Object().Value("code").IsEqual("NOT_ASSIGNED")
+
+	// последний итог заменяет прежний
+	review(first, "CHANGES_REQUESTED", http.StatusOK)
+	reviewers := review(first, "APPROVED", http.StatusOK).Value("pr").Object().Value("reviewers").Array()
+	reviewers.Value(0).Object().Value("verdict").IsEqual("APPROVED")
+	reviewers.Value(0).Object().ContainsKey("verdictAt")
+	reviewers.Value(1).Object().NotContainsKey("verdict")
+
+	// итог не переходит к замене
+	review(second, "COMMENTED", http.StatusOK)
+	replaced := e.POST("/pullRequest/reassign").
+		WithJSON(map[string]any{"pull_request_id": prID, "old_user_id": second}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pr").Object().Value("reviewers").Array().Value(1).Object()
+	replaced.Value("user_id").IsEqual(spare)
+	replaced.NotContainsKey("verdict")
+
+	e.POST("/pullRequest/merge").
+		WithJSON(map[string]any{"pull_request_id": prID}).
+		Expect().
+		Status(http.StatusOK)
+	review(first, "APPROVED", http.StatusConflict).Value("error").Object().Value("code").IsEqual("PR_MERGED")
+}
+
+// двадцать четвёртый сценарий — условия merge:
+// - команда требует два одобрения, блокирует merge при запрошенных изменениях и ждёт одобрения senior
+// - пока условия не выполнены, merge отвечает MERGE_BLOCKED со списком невыполненных
+// - после одобрения senior merge проходит и остаётся идемпотентным
+func TestPRService_E2E_MergePolicy(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-mergepolicy-%d", suffix)
+	author := fmt.Sprintf("mp0-%d", suffix)
+	middle := fmt.Sprintf("mp1-%d", suffix)
+	senior := fmt.Sprintf("mp2-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "PolicyAuthor", "is_active": true},
+				{"user_id": middle, "username": "Middle", "is_active": true, "seniority": "middle"},
+				{"user_id": senior, "username": "Senior", "is_active": true, "seniority": "senior"},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	settings := e.POST("/team/settings").
+		WithJSON(map[string]any{
+			"team_name":                        teamName,
+			"required_approvals":               2,
+			"block_on_changes_requested":       true,
+			"require_senior_or_owner_approval": true,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	settings.Value("required_approvals").IsEqual(2)
+	settings.Value("block_on_changes_requested").IsEqual(true)
+
+	prID := fmt.Sprintf("pr-mergepolicy-%d", suffix)
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":   prID,
+			"pull_request_name": "Policy",
+			"author_id":         author,
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().
+		Value("assigned_reviewers").Array().ContainsOnly(middle, senior)
+
+	review := func(userID, verdict string) {
+		e.POST("/pullRequest/review").
+			WithJSON(map[string]any{"pull_request_id": prID, "user_id": userID, "verdict": verdict}).
+			Expect().
+			Status(http.StatusOK)
+	}
+	merge := func(status int) *httpexpect.Object {
+		return e.POST("/pullRequest/merge").
+			WithJSON(map[string]any{"pull_request_id": prID}).
+			Expect().
+			Status(status).
+			JSON().Object()
+	}
+
+	review(middle, "APPROVED")
+	review(senior, "CHANGES_REQUESTED")
+
+	blocked := merge(http.StatusConflict).Value("error").Object()
+	blocked.Value("code").IsEqual("MERGE_BLOCKED")
+	blocked.Value("unmet_conditions").Array().IsEqual([]map[string]any{
+		{"code": "APPROVALS_REQUIRED", "required": 2, "actual": 1},
+		{"code": "CHANGES_REQUESTED", "user_ids": []string{senior}},
+		{"code": "SENIOR_OR_OWNER_APPROVAL_REQUIRED"},
+	})
+
+	review(senior, "APPROVED")
+	merge(http.StatusOK).Value("pr").Object().Value("status").IsEqual("MERGED")
+
+	// повторный merge идемпотентен
+	merge(http.StatusOK).Value("pr").Object().Value("status").IsEqual("MERGED")
+}
+
+// двадцать пятый сценарий — чтение и список PR:
+// - /pullRequest/get отдаёт PR по id, неизвестный id — 404
+// - список идёт от новых к старым и листается курсором
+// - фильтры по автору, ревьюверу, статусу и датам складываются, некорректные — INVALID_FILTER
+func TestPRService_E2E_GetAndListPullRequests(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-list-%d", suffix)
+	author := fmt.Sprintf("ls0-%d", suffix)
+	reviewer := fmt.Sprintf("ls1-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "ListAuthor", "is_active": true},
+				{"user_id": reviewer, "username": "ListReviewer", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	prIDs := make([]string, 3)
+	for i := range prIDs {
+		prIDs[i] = fmt.Sprintf("pr-list-%d-%d", suffix, i)
+		e.POST("/pullRequest/create").
+			WithJSON(map[string]any{
+				"pull_request_id":   prIDs[i],
+				"pull_request_name": "List",
+				"author_id":         author,
+			}).
+			Expect().
+			Status(http.StatusCreated)
+	}
+	e.POST("/pullRequest/merge").
+		WithJSON(map[string]any{"pull_request_id": prIDs[0]}).
+		Expect().
+		Status(http.StatusOK)
+
+	pr := e.GET("/pullRequest/get").
+		WithQuery("pull_request_id", prIDs[0]).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pr").Object()
+	pr.Value("status").IsEqual("MERGED")
+	pr.Value("assigned_reviewers").Array().IsEqual([]string{reviewer})
+
+	e.GET("/pullRequest/get").
+		WithQuery("pull_request_id", "missing-"+prIDs[0]).
+		Expect().
+		Status(http.StatusNotFound)
+
+	// новые первыми, по две на страницу
+	first := e.GET("/pullRequest/list").
+		WithQuery("team_name", teamName).
+		WithQuery("limit", 2).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	first.Value("pull_requests").Array().Length().IsEqual(2)
+	first.Value("pull_requests").Array().Value(0).Object().Value("pull_request_id").IsEqual(prIDs[2])
+	first.Value("pull_requests").Array().Value(1).Object().Value("pull_request_id").IsEqual(prIDs[1])
+	cursor := first.Value("next_cursor").String().NotEmpty().Raw()
+
+	last := e.GET("/pullRequest/list").
+		WithQuery("team_name", teamName).
+		WithQuery("limit", 2).
+		WithQuery("cursor", cursor).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	last.Value("pull_requests").Array().Length().IsEqual(1)
+	last.Value("pull_requests").Array().Value(0).Object().Value("pull_request_id").IsEqual(prIDs[0])
+	last.NotContainsKey("next_cursor")
+
+	// фильтры складываются
+	merged := e.GET("/pullRequest/list").
+		WithQuery("author_id", author).
+		WithQuery("reviewer_id", reviewer).
+		WithQuery("status", "MERGED").
+		WithQuery("merged_from", time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array()
+	merged.Length().IsEqual(1)
+	merged.Value(0).Object().Value("pull_request_id").IsEqual(prIDs[0])
+
+	e.GET("/pullRequest/list").
+		WithQuery("team_name", teamName).
+		WithQuery("created_to", time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pull_requests").Array().IsEmpty()
+
+	for _, bad := range []map[string]string{{"status": "DONE"}, {"cursor": "%%%"}, {"created_from": "yesterday"}} {
+		req := e.GET("/pullRequest/list")
+		for k, v := range bad {
+			req = req.WithQuery(k, v)
+		}
+		req.Expect().
+			Status(http.StatusBadRequest).
+			JSON().Object().Value("error").Object().Value("code").IsEqual("INVALID_FILTER")
+	}
+}
+
+// двадцать шестой сценарий — история PR:
+// - создание, назначение, ручная замена, передача при деактивации и merge пишутся событиями
+// - события идут в порядке появления, с причиной и автором действия
+// - история неизвестного PR — 404
+func TestPRService_E2E_PullRequestHistory(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-history-%d", suffix)
+	author := fmt.Sprintf("hs0-%d", suffix)
+	first := fmt.Sprintf("hs1-%d", suffix)
+	second := fmt.Sprintf("hs2-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "HistoryAuthor", "is_active": true},
+				{"user_id": first, "username": "First", "is_active": true},
+				{"user_id": second, "username": "Second", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1}).
+		Expect().
+		Status(http.StatusOK)
+
+	prID := fmt.Sprintf("pr-history-%d", suffix)
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":     prID,
+			"pull_request_name":   "History",
+			"author_id":           author,
+			"requested_reviewers": []string{first},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	// first -> second вручную, затем second -> first при деактивации: UPDATE в pr_reviewers это бы стёр
+	e.POST("/pullRequest/reassign").
+		WithJSON(map[string]any{"pull_request_id": prID, "old_user_id": first, "actor_id": author}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("replaced_by").IsEqual(second)
+
+	e.POST("/team/deactivateUsers").
+		WithJSON(map[string]any{"team_name": teamName, "user_ids": []string{second}}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/pullRequest/merge").
+		WithJSON(map[string]any{"pull_request_id": prID}).
+		Expect().
+		Status(http.StatusOK)
+
+	events := e.GET("/pullRequest/history").
+		WithQuery("pull_request_id", prID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("events").Array()
+	events.Length().IsEqual(5)
+
+	created := events.Value(0).Object()
+	created.Value("type").IsEqual("created")
+	created.Value("actor").IsEqual(author)
+
+	assigned := events.Value(1).Object()
+	assigned.Value("type").IsEqual("reviewer_assigned")
+	assigned.Value("user_id").IsEqual(first)
+	assigned.Value("reason").IsEqual("requested")
+
+	reassigned := events.Value(2).Object()
+	reassigned.Value("type").IsEqual("reviewer_replaced")
+	reassigned.Value("user_id").IsEqual(first)
+	reassigned.Value("replacement_id").IsEqual(second)
+	reassigned.Value("reason").IsEqual("REASSIGN")
+	reassigned.Value("actor").IsEqual(author)
+
+	handedOver := events.Value(3).Object()
+	handedOver.Value("type").IsEqual("reviewer_replaced")
+	handedOver.Value("user_id").IsEqual(second)
+	handedOver.Value("replacement_id").IsEqual(first)
+	handedOver.Value("reason").IsEqual("DEACTIVATED")
+	handedOver.Value("actor").IsEqual("system")
+
+	events.Value(4).Object().Value("type").IsEqual("merged")
+
+	e.GET("/pullRequest/history").
+		WithQuery("pull_request_id", fmt.Sprintf("pr-history-missing-%d", suffix)).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+// двадцать седьмой сценарий — SLA ревью:
+// - по умолчанию SLA нет, неизвестное действие при нарушении — INVALID_SETTINGS
+// - у назначения есть время начала, от которого считается срок
+// - настройка SLA сама ревью не трогает: нарушения находит планировщик
+// - статистика нарушений по команде, неизвестная команда — 404
+func TestPRService_E2E_ReviewSLA(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-sla-%d", suffix)
+	author := fmt.Sprintf("sl0-%d", suffix)
+	first := fmt.Sprintf("sl1-%d", suffix)
+	second := fmt.Sprintf("sl2-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "SLAAuthor", "is_active": true},
+				{"user_id": first, "username": "First", "is_active": true},
+				{"user_id": second, "username": "Second", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "reviewer_count": 1}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("review_sla").IsEqual("0s")
+
+	e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "review_sla_action": "PING"}).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object().Value("code").IsEqual("INVALID_SETTINGS")
+
+	prID := fmt.Sprintf("pr-sla-%d", suffix)
+	e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":     prID,
+			"pull_request_name":   "SLA",
+			"author_id":           author,
+			"requested_reviewers": []string{first},
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().Value("reviewers").Array().Value(0).Object().Value("timing").Object().ContainsKey("assignedAt")
+
+	// срок с запасом: планировщик не успеет найти нарушение во время теста
+	settings := e.POST("/team/settings").
+		WithJSON(map[string]any{"team_name": teamName, "review_sla": "24h", "review_sla_action": "REASSIGN"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	settings.Value("review_sla").IsEqual("24h0m0s")
+	settings.Value("review_sla_action").IsEqual("REASSIGN")
+
+	e.GET("/pullRequest/get").
+		WithQuery("pull_request_id", prID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pr").Object().Value("assigned_reviewers").Array().IsEqual([]string{first})
+
+	e.GET("/team/settings").
+		WithQuery("team_name", teamName).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("review_sla").IsEqual("24h0m0s")
+
+	stats := e.GET("/stats/sla").
+		WithQuery("team_name", teamName).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	stats.Value("team_name").IsEqual(teamName)
+	stats.Value("total_breaches").IsEqual(0)
+	stats.Value("breaches").Array().IsEmpty()
+
+	e.GET("/stats/sla").
+		WithQuery("team_name", fmt.Sprintf("team-sla-missing-%d", suffix)).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+// двадцать восьмой сценарий — тихие часы:
+// - начало и конец задаются вместе, совпадать не могут и должны быть в формате HH:MM
+// - окно может переходить через полночь в часовом поясе пользователя
+// - пустые значения снимают тихие часы
+func TestPRService_E2E_QuietHours(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-quiet-%d", suffix)
+	user := fmt.Sprintf("qh1-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": user, "username": "Quiet", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	for _, body := range []map[string]any{
+		{"user_id": user, "quiet_start": "22:00"},
+		{"user_id": user, "quiet_start": "22:00", "quiet_end": "22:00"},
+		{"user_id": user, "quiet_start": "25:00", "quiet_end": "08:00"},
+	} {
+		e.POST("/users/update").
+			WithJSON(body).
+			Expect().
+			Status(http.StatusBadRequest).
+			JSON().Object().Value("error").Object().
+			Value("code").String().IsEqual("INVALID_QUIET_HOURS")
+	}
+
+	// окно через полночь в часовом поясе пользователя
+	res := e.POST("/users/update").
+		WithJSON(map[string]any{"user_id": user, "timezone": "Europe/Berlin", "quiet_start": "22:00", "quiet_end": "08:00"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("user").Object()
+	res.Value("quiet_start").IsEqual("22:00")
+	res.Value("quiet_end").IsEqual("08:00")
+
+	// пустые значения снимают тихие часы
+	e.POST("/users/update").
+		WithJSON(map[string]any{"user_id": user, "quiet_start": "", "quiet_end": ""}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("user").Object().NotContainsKey("quiet_start")
+}
+
+// двадцать девятый сценарий — хронология ревью:
+// - при назначении известно только время назначения
+// - первый комментарий фиксирует первое действие, первый итог — завершение
+// - последующие итоги эти отметки не сдвигают, они видны и в /pullRequest/get
+func TestPRService_E2E_ReviewerTiming(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+	e := httpexpect.Default(t, u.String())
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("team-timing-%d", suffix)
+	author := fmt.Sprintf("tm0-%d", suffix)
+	reviewer := fmt.Sprintf("tm1-%d", suffix)
+
+	e.POST("/team/add").
+		WithJSON(map[string]any{
+			"team_name": teamName,
+			"members": []map[string]any{
+				{"user_id": author, "username": "TimingAuthor", "is_active": true},
+				{"user_id": reviewer, "username": "Reviewer", "is_active": true},
+			},
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	prID := fmt.Sprintf("pr-timing-%d", suffix)
+	timing := e.POST("/pullRequest/create").
+		WithJSON(map[string]any{
+			"pull_request_id":     prID,
+			"pull_request_name":   "Timing",
+			"author_id":           author,
+			"requested_reviewers": []string{reviewer},
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("pr").Object().Value("reviewers").Array().Value(0).Object().Value("timing").Object()
+	timing.ContainsKey("assignedAt")
+	timing.NotContainsKey("firstActionAt")
+	timing.NotContainsKey("completedAt")
+
+	review := func(verdict string) *httpexpect.Object {
+		return e.POST("/pullRequest/review").
+			WithJSON(map[string]any{"pull_request_id": prID, "user_id": reviewer, "verdict": verdict}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("pr").Object().Value("reviewers").Array().Value(0).Object().Value("timing").Object()
+	}
+
+	// комментарий — первое действие, но не итог
+	timing = review("COMMENTED")
+	timing.ContainsKey("firstActionAt")
+	timing.Value("time_to_first_action_seconds").Number().Ge(0)
+	timing.NotContainsKey("completedAt")
+	firstActionAt := timing.Value("firstActionAt").String().Raw()
+
+	timing = review("APPROVED")
+	timing.Value("firstActionAt").IsEqual(firstActionAt)
+	timing.ContainsKey("completedAt")
+	timing.Value("time_to_complete_seconds").Number().Ge(0)
+	completedAt := timing.Value("completedAt").String().Raw()
+
+	// completedAt фиксирует первый итог и не сдвигается
+	review("CHANGES_REQUESTED").Value("completedAt").IsEqual(completedAt)
+
+	// хронология видна и в /pullRequest/get
+	e.GET("/pullRequest/get").
+		WithQuery("pull_request_id", prID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("pr").Object().Value("reviewers").Array().Value(0).Object().
+		Value("timing").Object().Value("completedAt").IsEqual(completedAt)
+}